@@ -17,10 +17,20 @@ import (
 
 var carId string
 
+// Session to race in, empty for the server's default session
+var sessionId = os.Getenv("SESSION_ID")
+
 const (
-	inputRate       = time.Second / 60 // 60 updates per second
-	lookAheadPoints = 8                // how many centerline points to look ahead
-	maxOffTrackDist = 40.0             // consider off-track if farther than this (tune)
+	inputRate       = time.Second / 60    // 60 updates per second
+	lookAheadPoints = 8                   // how many centerline points to look ahead
+	maxOffTrackDist = 40.0                // consider off-track if farther than this (tune)
+	pitEntryWindow  = 4 * lookAheadPoints // centerline points before the pit entry to turn in from
+	trackWindow     = 4 * lookAheadPoints // centerline points past the last nearest one searched first
+	brakingPoints   = 80                  // centerline points ahead checked for corners to brake for
+	cornerSpan      = 3                   // points either side used to measure a corner
+	cornerAccel     = 10.0                // m/s², sideways grip the bot trusts itself with
+	brakingDecel    = 8.0                 // m/s², braking the bot plans with
+	pitSpeedMargin  = 0.85                // fraction of the pit speed limit to drive at
 )
 
 func getServerAddr() string {
@@ -34,10 +44,22 @@ func getServerAddr() string {
 type CarClient struct {
 	client     pb.CarServiceClient
 	conn       *grpc.ClientConn
-	sequence   int32 // kept for local logging/debug, not sent
+	sequence   uint64                    // numbers the inputs sent
+	drive      pb.CarService_DriveClient // input stream, nil when inputs go unary
 	myCarState *pb.CarState
-	centerline []Point // computed centerline points
+	centerline []Point   // computed centerline points
+	trackIdx   int       // centerline point the car was last nearest, -1 when not known
+	cornering  []float64 // fastest speed through each centerline point, m/s
 	raceType   pb.RaceType
+	simMode    pb.SimulationMode
+	phase      pb.RacePhase
+	gameTick   int32 // of the latest race update
+
+	pitLane    []Point        // lane centerline from entry to exit, empty without a pit lane
+	pitEntry   int            // centerline point nearest the pit lane entry
+	pitLimit   float64        // m/s
+	pitPenalty *pb.CarPenalty // penalty to serve in the pit lane, nil for none
+	inPitLane  bool
 }
 
 type Point struct {
@@ -71,6 +93,7 @@ func (c *CarClient) checkIn(ctx context.Context) error {
 		CarId:      carId,
 		PlayerName: "AI Driver " + carId,
 		Password:   "", // Demo mode
+		SessionId:  sessionId,
 	})
 	if err != nil {
 		return fmt.Errorf("CheckIn failed: %v", err)
@@ -81,6 +104,9 @@ func (c *CarClient) checkIn(ctx context.Context) error {
 	}
 
 	log.Printf("✓ %s", resp.Message)
+	if resp.SessionId != "" {
+		log.Printf("Session: %s", resp.SessionId)
+	}
 	if resp.IsSpectator {
 		log.Printf("Logged in as spectator")
 	}
@@ -89,6 +115,17 @@ func (c *CarClient) checkIn(ctx context.Context) error {
 	c.raceType = resp.Race
 	log.Printf("Race type: %s", c.raceType.String())
 
+	// Store simulation mode
+	c.simMode = resp.SimMode
+	log.Printf("Simulation mode: %s", c.simMode.String())
+
+	// Log our car's specs
+	for _, car := range resp.Cars {
+		if car.CarId == carId {
+			log.Printf("Car specs: power %.0f, weight %.0f kg", car.Power, car.Weight)
+		}
+	}
+
 	// Load track from check-in response
 	if resp.Track != nil {
 		c.loadTrackFromInfo(resp.Track)
@@ -111,6 +148,7 @@ func (c *CarClient) loadTrackFromInfo(track *pb.TrackInfo) {
 	// Use the shorter length to avoid index-out-of-range
 	n := int(math.Min(float64(len(track.LeftBoundary)), float64(len(track.RightBoundary))))
 	c.centerline = make([]Point, n)
+	c.trackIdx = -1
 
 	for i := 0; i < n; i++ {
 		l := track.LeftBoundary[i]
@@ -122,11 +160,27 @@ func (c *CarClient) loadTrackFromInfo(track *pb.TrackInfo) {
 	}
 
 	log.Printf("Centerline computed with %d points", len(c.centerline))
+	c.cornering = cornerSpeeds(c.centerline)
+
+	c.pitLane = nil
+	if lane := track.PitLane; lane != nil {
+		n := int(math.Min(float64(len(lane.LeftBoundary)), float64(len(lane.RightBoundary))))
+		for i := 0; i < n; i++ {
+			l, r := lane.LeftBoundary[i], lane.RightBoundary[i]
+			c.pitLane = append(c.pitLane, Point{
+				X: (float64(l.X) + float64(r.X)) / 2,
+				Y: (float64(l.Y) + float64(r.Y)) / 2,
+			})
+		}
+		c.pitLimit = float64(lane.SpeedLimit)
+		c.pitEntry = nearest(c.centerline, c.pitLane[0])
+		log.Printf("Pit lane with %d points, limit %.1f m/s", len(c.pitLane), c.pitLimit)
+	}
 }
 
 // Fetch track boundaries and compute simple centerline (alternative method)
 func (c *CarClient) loadTrack(ctx context.Context) error {
-	track, err := c.client.GetTrack(ctx, &pb.Empty{})
+	track, err := c.client.GetTrack(ctx, &pb.SessionRequest{SessionId: sessionId})
 	if err != nil {
 		return fmt.Errorf("GetTrack failed: %v", err)
 	}
@@ -136,7 +190,7 @@ func (c *CarClient) loadTrack(ctx context.Context) error {
 }
 
 func (c *CarClient) streamUpdates(ctx context.Context) error {
-	stream, err := c.client.StreamRaceUpdates(ctx, &pb.Empty{})
+	stream, err := c.client.StreamRaceUpdates(ctx, &pb.SessionRequest{SessionId: sessionId})
 	if err != nil {
 		return fmt.Errorf("failed to start stream: %v", err)
 	}
@@ -151,11 +205,13 @@ func (c *CarClient) streamUpdates(ctx context.Context) error {
 			return fmt.Errorf("stream recv error: %v", err)
 		}
 
-		c.handleUpdate(update)
+		c.handleUpdate(ctx, update)
 	}
 }
 
-func (c *CarClient) handleUpdate(update *pb.RaceUpdate) {
+func (c *CarClient) handleUpdate(ctx context.Context, update *pb.RaceUpdate) {
+	c.gameTick = update.GameTick
+
 	// Update our own car state
 	for _, car := range update.Cars {
 		if car.CarId == carId {
@@ -168,9 +224,13 @@ func (c *CarClient) handleUpdate(update *pb.RaceUpdate) {
 		}
 	}
 
-	// Log penalties if any
+	// Log penalties if any, and note one that has to be served in the pit lane
+	c.pitPenalty = nil
 	if len(update.Penalties) > 0 {
 		for _, penalty := range update.Penalties {
+			if penalty.CarId == carId && penalty.Kind != pb.PenaltyKind_PENALTY_TIME {
+				c.pitPenalty = penalty
+			}
 			if penalty.CarId == carId {
 				log.Printf("⚠️  PENALTY: %s (remaining: %.1fs)",
 					penalty.Reason, float64(penalty.RemainingPenalty)/1000.0)
@@ -178,31 +238,88 @@ func (c *CarClient) handleUpdate(update *pb.RaceUpdate) {
 		}
 	}
 
+	// Log contacts involving our car
+	for _, contact := range update.Contacts {
+		if contact.CarA == carId || contact.CarB == carId {
+			log.Printf("💥 CONTACT: %s ↔ %s (impulse: %.0f)",
+				contact.CarA, contact.CarB, contact.Impulse)
+		}
+	}
+
 	// Race status
 	if update.RaceStatus != nil {
 		st := update.RaceStatus
+		if st.Phase != c.phase {
+			c.phase = st.Phase
+			log.Printf("🚦 Race phase: %s", c.phase.String())
+		}
+		if st.Phase == pb.RacePhase_PHASE_START_LIGHTS {
+			log.Printf("🚦 Lights: %d", st.LightsOn)
+		}
+		if st.RaceType != c.raceType {
+			c.raceType = st.RaceType
+			log.Printf("Session changed to %s", c.raceType.String())
+		}
 		if st.Status == "finished" {
 			log.Printf("🏁 RACE FINISHED — tick: %d (laps: %d)",
 				st.GameTick, st.TotalLaps)
 		}
 	}
+
+	// In lockstep the server waits for our input before the next tick
+	if c.simMode == pb.SimulationMode_LOCKSTEP {
+		steering, throttle, brake := c.getAIInput()
+		if err := c.sendInput(ctx, steering, throttle, brake); err != nil {
+			log.Printf("Input error: %v", err)
+		}
+	}
+}
+
+// Open the Drive stream for inputs. Its acks are drained, the race update
+// stream already carries the car's state.
+func (c *CarClient) openDrive(ctx context.Context) {
+	stream, err := c.client.Drive(ctx)
+	if err != nil {
+		log.Printf("Drive stream unavailable, sending inputs unary: %v", err)
+		return
+	}
+	c.drive = stream
+
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err != io.EOF {
+					log.Printf("Drive stream ended: %v", err)
+				}
+				return
+			}
+		}
+	}()
 }
 
 func (c *CarClient) sendInput(ctx context.Context, steering, throttle, brake float32) error {
-	c.sequence++ // local counter only — not sent to server
+	c.sequence++
 
-	ts := time.Now().UnixMilli()
-	if ts > math.MaxInt32 {
-		ts = math.MaxInt32 // very defensive
+	// Inputs answer the latest update, so they are for the tick after it
+	input := &pb.PlayerInput{
+		CarId:      carId,
+		AuthToken:  getAuthToken(carId),
+		Steering:   steering,
+		Throttle:   throttle,
+		Brake:      brake,
+		SessionId:  sessionId,
+		Sequence:   c.sequence,
+		TargetTick: c.gameTick + 1,
 	}
 
-	input := &pb.PlayerInput{
-		CarId:     carId,
-		AuthToken: getAuthToken(carId),
-		Steering:  steering,
-		Throttle:  throttle,
-		Brake:     brake,
-		Timestamp: int32(ts),
+	// Over the stream the session, car and token only matter on the first
+	// input, and acks come back per tick
+	if c.drive != nil {
+		if err := c.drive.Send(input); err == nil {
+			return nil
+		}
+		log.Printf("Drive stream closed, sending inputs unary")
+		c.drive = nil
 	}
 
 	ack, err := c.client.SendPlayerInput(ctx, input)
@@ -222,6 +339,12 @@ func (c *CarClient) sendInput(ctx context.Context, steering, throttle, brake flo
 // Basic centerline following (look-ahead steering)
 // ────────────────────────────────────────────────
 func (c *CarClient) getAIInput() (steering, throttle, brake float32) {
+	// Hold still until the lights go out, throttle before then is a jump start
+	if c.phase < pb.RacePhase_PHASE_RACING {
+		c.trackIdx = -1
+		return 0, 0, 1.0
+	}
+
 	if c.myCarState == nil || len(c.centerline) == 0 {
 		return 0, 0.6, 0 // safe fallback
 	}
@@ -231,25 +354,151 @@ func (c *CarClient) getAIInput() (steering, throttle, brake float32) {
 		return 0, 0, 1.0 // Full brake during penalty
 	}
 
+	steering, angleError, minDist, closestIdx := c.follow(c.centerline, true, c.trackIdx)
+	c.trackIdx = closestIdx
+
+	// Turn into the pit lane to serve a penalty there, once the car is on
+	// the stretch of track leading to the entry
+	if c.pitPenalty != nil && !c.inPitLane && len(c.pitLane) > 0 {
+		toEntry := (c.pitEntry - closestIdx + len(c.centerline)) % len(c.centerline)
+		if toEntry < pitEntryWindow {
+			c.inPitLane = true
+			log.Printf("🔧 Entering the pit lane for a %s penalty", c.pitPenalty.Kind)
+		}
+	}
+	if c.inPitLane {
+		return c.getPitInput()
+	}
+
+	// Throttle & brake logic: brake for the corners coming up in time to
+	// take them
+	throttle = 0.9
+	brake = 0.0
+	if speed := float64(c.myCarState.Speed); speed > c.targetSpeed(closestIdx) {
+		throttle = 0
+		brake = 0.6
+	}
+
+	// Slow down when far off track or sharp correction needed
+	if minDist > maxOffTrackDist || math.Abs(angleError) > 65 {
+		throttle = 0.45
+		brake = 0.3
+	}
+
+	return steering, throttle, brake
+}
+
+// Steer along the pit lane under the speed limit, stopping for a stop-go
+// penalty until it is served
+func (c *CarClient) getPitInput() (steering, throttle, brake float32) {
+	steering, _, _, closestIdx := c.follow(c.pitLane, false, -1)
+	if closestIdx >= len(c.pitLane)-2 {
+		c.inPitLane = false
+		c.trackIdx = -1
+		log.Printf("🔧 Leaving the pit lane")
+	}
+
+	// Stop halfway down the lane, inside the speed limit, for a stop-go
+	if c.pitPenalty != nil && c.pitPenalty.Kind == pb.PenaltyKind_PENALTY_STOP_GO &&
+		closestIdx >= len(c.pitLane)/2 {
+		return steering, 0, 1.0
+	}
+
+	if float64(c.myCarState.Speed) > c.pitLimit*pitSpeedMargin {
+		return steering, 0, 0.5
+	}
+	return steering, 0.5, 0
+}
+
+// Fastest speed through each point of a closed path, from the radius of the
+// circle through its neighbours cornerSpan points away
+func cornerSpeeds(path []Point) []float64 {
+	n := len(path)
+	speeds := make([]float64, n)
+	for i := range path {
+		a, b, c := path[(i-cornerSpan+n)%n], path[i], path[(i+cornerSpan)%n]
+		ab := math.Hypot(b.X-a.X, b.Y-a.Y)
+		bc := math.Hypot(c.X-b.X, c.Y-b.Y)
+		ca := math.Hypot(a.X-c.X, a.Y-c.Y)
+		cross := math.Abs((b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X))
+		if cross == 0 {
+			speeds[i] = math.Inf(1)
+			continue
+		}
+		radius := ab * bc * ca / (2 * cross)
+		speeds[i] = math.Sqrt(cornerAccel * radius)
+	}
+	return speeds
+}
+
+// Fastest the car can go at a centerline point and still slow down in time
+// for every corner in the next brakingPoints
+func (c *CarClient) targetSpeed(from int) float64 {
+	n := len(c.centerline)
+	target, distance := math.Inf(1), 0.0
+	for k := 0; k < brakingPoints; k++ {
+		i := (from + k) % n
+		if k > 0 {
+			prev := c.centerline[(i-1+n)%n]
+			distance += math.Hypot(c.centerline[i].X-prev.X, c.centerline[i].Y-prev.Y)
+		}
+		corner := c.cornering[i]
+		target = math.Min(target, math.Sqrt(corner*corner+2*brakingDecel*distance))
+	}
+	return target
+}
+
+// Index of the path point nearest to p
+func nearest(path []Point, p Point) int {
+	best, bestDist := 0, math.MaxFloat64
+	for i, q := range path {
+		if dist := math.Hypot(q.X-p.X, q.Y-p.Y); dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// Steer towards a point a little further along a path from the point closest
+// to the car. A path that does not loop ends at its last point. On a loop,
+// from is the point the car was last nearest, the search starts around it
+// so parts of the track that lie close together are not mixed up; -1 or a
+// car far from there searches the whole path.
+func (c *CarClient) follow(path []Point, loop bool, from int) (steering float32, angleError, minDist float64, closestIdx int) {
 	pos := c.myCarState.Position
 	heading := float64(c.myCarState.Heading) // convert to float64
 
-	// Find closest point on centerline (naive linear search)
-	minDist := math.MaxFloat64
-	closestIdx := 0
-	for i, p := range c.centerline {
-		dx := p.X - float64(pos.X)
-		dy := p.Y - float64(pos.Y)
-		dist := math.Sqrt(dx*dx + dy*dy)
-		if dist < minDist {
+	closest := func(i int) {
+		dx := path[i].X - float64(pos.X)
+		dy := path[i].Y - float64(pos.Y)
+		if dist := math.Sqrt(dx*dx + dy*dy); dist < minDist {
 			minDist = dist
 			closestIdx = i
 		}
 	}
 
+	minDist = math.MaxFloat64
+	if loop && from >= 0 {
+		for i := from - lookAheadPoints; i <= from+trackWindow; i++ {
+			closest((i + len(path)) % len(path))
+		}
+	}
+	if minDist > 2*maxOffTrackDist {
+		// Find closest point on the path (naive linear search)
+		minDist = math.MaxFloat64
+		for i := range path {
+			closest(i)
+		}
+	}
+
 	// Look ahead several points
-	targetIdx := (closestIdx + lookAheadPoints) % len(c.centerline)
-	target := c.centerline[targetIdx]
+	targetIdx := closestIdx + lookAheadPoints
+	if loop {
+		targetIdx %= len(path)
+	} else if targetIdx >= len(path) {
+		targetIdx = len(path) - 1
+	}
+	target := path[targetIdx]
 
 	dx := target.X - float64(pos.X)
 	dy := target.Y - float64(pos.Y)
@@ -258,23 +507,12 @@ func (c *CarClient) getAIInput() (steering, throttle, brake float32) {
 	desiredHeading := math.Atan2(dy, dx) * 180 / math.Pi
 
 	// Angle error (shortest direction, normalized -180..180)
-	angleError := math.Mod(desiredHeading-heading+540, 360) - 180
+	angleError = math.Mod(desiredHeading-heading+540, 360) - 180
 
 	// Proportional steering
 	steering = float32(angleError / 45.0) // tune divisor: smaller = sharper turns
 	steering = max(-1, min(1, steering))
-
-	// Throttle & brake logic
-	throttle = 0.9
-	brake = 0.0
-
-	// Slow down when far off track or sharp correction needed
-	if minDist > maxOffTrackDist || math.Abs(angleError) > 65 {
-		throttle = 0.45
-		brake = 0.3
-	}
-
-	return steering, throttle, brake
+	return steering, angleError, minDist, closestIdx
 }
 
 func min(a, b float32) float32 {
@@ -304,6 +542,16 @@ func main() {
 	if err := client.checkIn(ctx); err != nil {
 		log.Fatalf("Failed to check in: %v", err)
 	}
+	client.openDrive(ctx)
+
+	// In lockstep, inputs are sent in reply to each race update
+	if client.simMode == pb.SimulationMode_LOCKSTEP {
+		log.Printf("AI driver started — Car %s — lockstep centerline follower", carId)
+		if err := client.streamUpdates(ctx); err != nil {
+			log.Fatalf("Stream error: %v", err)
+		}
+		return
+	}
 
 	// Start background stream of race updates
 	go func() {
@@ -23,20 +23,44 @@ const (
 	CarService_GetTrack_FullMethodName          = "/car.CarService/GetTrack"
 	CarService_StreamRaceUpdates_FullMethodName = "/car.CarService/StreamRaceUpdates"
 	CarService_SendPlayerInput_FullMethodName   = "/car.CarService/SendPlayerInput"
+	CarService_Drive_FullMethodName             = "/car.CarService/Drive"
+	CarService_ListSessions_FullMethodName      = "/car.CarService/ListSessions"
+	CarService_CreateSession_FullMethodName     = "/car.CarService/CreateSession"
+	CarService_JoinSession_FullMethodName       = "/car.CarService/JoinSession"
+	CarService_GetResults_FullMethodName        = "/car.CarService/GetResults"
+	CarService_ListTracks_FullMethodName        = "/car.CarService/ListTracks"
 )
 
 // CarServiceClient is the client API for CarService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Every call is routed to a session by its session_id, an empty id means the
+// default session.
 type CarServiceClient interface {
 	// Client checks in and receives static data (cars, track)
 	CheckIn(ctx context.Context, in *RegisterPlayer, opts ...grpc.CallOption) (*CheckInResponse, error)
 	// Get track info only (no authentication needed)
-	GetTrack(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrackInfo, error)
+	GetTrack(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*TrackInfo, error)
 	// Stream race updates to all clients (spectators + players)
-	StreamRaceUpdates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RaceUpdate], error)
+	StreamRaceUpdates(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RaceUpdate], error)
 	// Players send input via unary request-response (grpc-web safe)
 	SendPlayerInput(ctx context.Context, in *PlayerInput, opts ...grpc.CallOption) (*InputAck, error)
+	// Players on native gRPC send input over one stream instead. The first
+	// input names the session and car and carries the token, later ones only
+	// need the controls. Every tick is acknowledged with the car's own state.
+	Drive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PlayerInput, DriveUpdate], error)
+	// Lobbies: every session has its own track, cars and tick loop. Creating
+	// one needs the admin token in the "x-admin-token" metadata header, and
+	// lobbies nobody has used for a while are closed.
+	ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SessionList, error)
+	CreateSession(ctx context.Context, in *SessionConfig, opts ...grpc.CallOption) (*SessionInfo, error)
+	// Check in to the first free car of a session, or car_id if given
+	JoinSession(ctx context.Context, in *RegisterPlayer, opts ...grpc.CallOption) (*CheckInResponse, error)
+	// Classification of the session, final once it is over
+	GetResults(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*Results, error)
+	// Tracks in the tracks directory, any of them can be picked per session
+	ListTracks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrackList, error)
 }
 
 type carServiceClient struct {
@@ -57,7 +81,7 @@ func (c *carServiceClient) CheckIn(ctx context.Context, in *RegisterPlayer, opts
 	return out, nil
 }
 
-func (c *carServiceClient) GetTrack(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrackInfo, error) {
+func (c *carServiceClient) GetTrack(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*TrackInfo, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TrackInfo)
 	err := c.cc.Invoke(ctx, CarService_GetTrack_FullMethodName, in, out, cOpts...)
@@ -67,13 +91,13 @@ func (c *carServiceClient) GetTrack(ctx context.Context, in *Empty, opts ...grpc
 	return out, nil
 }
 
-func (c *carServiceClient) StreamRaceUpdates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RaceUpdate], error) {
+func (c *carServiceClient) StreamRaceUpdates(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RaceUpdate], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &CarService_ServiceDesc.Streams[0], CarService_StreamRaceUpdates_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, RaceUpdate]{ClientStream: stream}
+	x := &grpc.GenericClientStream[SessionRequest, RaceUpdate]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -96,18 +120,99 @@ func (c *carServiceClient) SendPlayerInput(ctx context.Context, in *PlayerInput,
 	return out, nil
 }
 
+func (c *carServiceClient) Drive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PlayerInput, DriveUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CarService_ServiceDesc.Streams[1], CarService_Drive_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PlayerInput, DriveUpdate]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CarService_DriveClient = grpc.BidiStreamingClient[PlayerInput, DriveUpdate]
+
+func (c *carServiceClient) ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SessionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, CarService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) CreateSession(ctx context.Context, in *SessionConfig, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, CarService_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) JoinSession(ctx context.Context, in *RegisterPlayer, opts ...grpc.CallOption) (*CheckInResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckInResponse)
+	err := c.cc.Invoke(ctx, CarService_JoinSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) GetResults(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*Results, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Results)
+	err := c.cc.Invoke(ctx, CarService_GetResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) ListTracks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrackList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrackList)
+	err := c.cc.Invoke(ctx, CarService_ListTracks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CarServiceServer is the server API for CarService service.
 // All implementations must embed UnimplementedCarServiceServer
 // for forward compatibility.
+//
+// Every call is routed to a session by its session_id, an empty id means the
+// default session.
 type CarServiceServer interface {
 	// Client checks in and receives static data (cars, track)
 	CheckIn(context.Context, *RegisterPlayer) (*CheckInResponse, error)
 	// Get track info only (no authentication needed)
-	GetTrack(context.Context, *Empty) (*TrackInfo, error)
+	GetTrack(context.Context, *SessionRequest) (*TrackInfo, error)
 	// Stream race updates to all clients (spectators + players)
-	StreamRaceUpdates(*Empty, grpc.ServerStreamingServer[RaceUpdate]) error
+	StreamRaceUpdates(*SessionRequest, grpc.ServerStreamingServer[RaceUpdate]) error
 	// Players send input via unary request-response (grpc-web safe)
 	SendPlayerInput(context.Context, *PlayerInput) (*InputAck, error)
+	// Players on native gRPC send input over one stream instead. The first
+	// input names the session and car and carries the token, later ones only
+	// need the controls. Every tick is acknowledged with the car's own state.
+	Drive(grpc.BidiStreamingServer[PlayerInput, DriveUpdate]) error
+	// Lobbies: every session has its own track, cars and tick loop. Creating
+	// one needs the admin token in the "x-admin-token" metadata header, and
+	// lobbies nobody has used for a while are closed.
+	ListSessions(context.Context, *Empty) (*SessionList, error)
+	CreateSession(context.Context, *SessionConfig) (*SessionInfo, error)
+	// Check in to the first free car of a session, or car_id if given
+	JoinSession(context.Context, *RegisterPlayer) (*CheckInResponse, error)
+	// Classification of the session, final once it is over
+	GetResults(context.Context, *SessionRequest) (*Results, error)
+	// Tracks in the tracks directory, any of them can be picked per session
+	ListTracks(context.Context, *Empty) (*TrackList, error)
 	mustEmbedUnimplementedCarServiceServer()
 }
 
@@ -121,15 +226,33 @@ type UnimplementedCarServiceServer struct{}
 func (UnimplementedCarServiceServer) CheckIn(context.Context, *RegisterPlayer) (*CheckInResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CheckIn not implemented")
 }
-func (UnimplementedCarServiceServer) GetTrack(context.Context, *Empty) (*TrackInfo, error) {
+func (UnimplementedCarServiceServer) GetTrack(context.Context, *SessionRequest) (*TrackInfo, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetTrack not implemented")
 }
-func (UnimplementedCarServiceServer) StreamRaceUpdates(*Empty, grpc.ServerStreamingServer[RaceUpdate]) error {
+func (UnimplementedCarServiceServer) StreamRaceUpdates(*SessionRequest, grpc.ServerStreamingServer[RaceUpdate]) error {
 	return status.Error(codes.Unimplemented, "method StreamRaceUpdates not implemented")
 }
 func (UnimplementedCarServiceServer) SendPlayerInput(context.Context, *PlayerInput) (*InputAck, error) {
 	return nil, status.Error(codes.Unimplemented, "method SendPlayerInput not implemented")
 }
+func (UnimplementedCarServiceServer) Drive(grpc.BidiStreamingServer[PlayerInput, DriveUpdate]) error {
+	return status.Error(codes.Unimplemented, "method Drive not implemented")
+}
+func (UnimplementedCarServiceServer) ListSessions(context.Context, *Empty) (*SessionList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedCarServiceServer) CreateSession(context.Context, *SessionConfig) (*SessionInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedCarServiceServer) JoinSession(context.Context, *RegisterPlayer) (*CheckInResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JoinSession not implemented")
+}
+func (UnimplementedCarServiceServer) GetResults(context.Context, *SessionRequest) (*Results, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetResults not implemented")
+}
+func (UnimplementedCarServiceServer) ListTracks(context.Context, *Empty) (*TrackList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTracks not implemented")
+}
 func (UnimplementedCarServiceServer) mustEmbedUnimplementedCarServiceServer() {}
 func (UnimplementedCarServiceServer) testEmbeddedByValue()                    {}
 
@@ -170,7 +293,7 @@ func _CarService_CheckIn_Handler(srv interface{}, ctx context.Context, dec func(
 }
 
 func _CarService_GetTrack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(SessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -182,17 +305,17 @@ func _CarService_GetTrack_Handler(srv interface{}, ctx context.Context, dec func
 		FullMethod: CarService_GetTrack_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CarServiceServer).GetTrack(ctx, req.(*Empty))
+		return srv.(CarServiceServer).GetTrack(ctx, req.(*SessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _CarService_StreamRaceUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(SessionRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(CarServiceServer).StreamRaceUpdates(m, &grpc.GenericServerStream[Empty, RaceUpdate]{ServerStream: stream})
+	return srv.(CarServiceServer).StreamRaceUpdates(m, &grpc.GenericServerStream[SessionRequest, RaceUpdate]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
@@ -216,6 +339,103 @@ func _CarService_SendPlayerInput_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CarService_Drive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CarServiceServer).Drive(&grpc.GenericServerStream[PlayerInput, DriveUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CarService_DriveServer = grpc.BidiStreamingServer[PlayerInput, DriveUpdate]
+
+func _CarService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).ListSessions(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarService_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).CreateSession(ctx, req.(*SessionConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_JoinSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterPlayer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).JoinSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarService_JoinSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).JoinSession(ctx, req.(*RegisterPlayer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_GetResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).GetResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarService_GetResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).GetResults(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_ListTracks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).ListTracks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarService_ListTracks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).ListTracks(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CarService_ServiceDesc is the grpc.ServiceDesc for CarService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -235,6 +455,26 @@ var CarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendPlayerInput",
 			Handler:    _CarService_SendPlayerInput_Handler,
 		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _CarService_ListSessions_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _CarService_CreateSession_Handler,
+		},
+		{
+			MethodName: "JoinSession",
+			Handler:    _CarService_JoinSession_Handler,
+		},
+		{
+			MethodName: "GetResults",
+			Handler:    _CarService_GetResults_Handler,
+		},
+		{
+			MethodName: "ListTracks",
+			Handler:    _CarService_ListTracks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -242,6 +482,584 @@ var CarService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _CarService_StreamRaceUpdates_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Drive",
+			Handler:       _CarService_Drive_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "car.proto",
+}
+
+const (
+	RaceControlService_CreateSession_FullMethodName  = "/car.RaceControlService/CreateSession"
+	RaceControlService_StartSession_FullMethodName   = "/car.RaceControlService/StartSession"
+	RaceControlService_PauseSession_FullMethodName   = "/car.RaceControlService/PauseSession"
+	RaceControlService_ResumeSession_FullMethodName  = "/car.RaceControlService/ResumeSession"
+	RaceControlService_AbortSession_FullMethodName   = "/car.RaceControlService/AbortSession"
+	RaceControlService_RestartSession_FullMethodName = "/car.RaceControlService/RestartSession"
+	RaceControlService_ChangeTrack_FullMethodName    = "/car.RaceControlService/ChangeTrack"
+	RaceControlService_AddCar_FullMethodName         = "/car.RaceControlService/AddCar"
+	RaceControlService_RemoveCar_FullMethodName      = "/car.RaceControlService/RemoveCar"
+	RaceControlService_IssuePenalty_FullMethodName   = "/car.RaceControlService/IssuePenalty"
+	RaceControlService_RescindPenalty_FullMethodName = "/car.RaceControlService/RescindPenalty"
+	RaceControlService_GetRaceState_FullMethodName   = "/car.RaceControlService/GetRaceState"
+	RaceControlService_CloseSession_FullMethodName   = "/car.RaceControlService/CloseSession"
+)
+
+// RaceControlServiceClient is the client API for RaceControlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Race control, for admins only. Every call must carry the admin token in
+// the "x-admin-token" metadata header, and acts on the session named in the
+// "x-session-id" header (the default session if there is none).
+type RaceControlServiceClient interface {
+	// Replace the current session, it stays NOTREADY until started
+	CreateSession(ctx context.Context, in *SessionConfig, opts ...grpc.CallOption) (*ControlAck, error)
+	StartSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+	PauseSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+	ResumeSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+	AbortSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+	// Start the current session over with the same setup
+	RestartSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+	ChangeTrack(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*ControlAck, error)
+	AddCar(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error)
+	RemoveCar(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error)
+	IssuePenalty(ctx context.Context, in *CarPenalty, opts ...grpc.CallOption) (*ControlAck, error)
+	RescindPenalty(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error)
+	GetRaceState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RaceControlState, error)
+	// Close a lobby and stop its tick loop, the default session stays
+	CloseSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error)
+}
+
+type raceControlServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRaceControlServiceClient(cc grpc.ClientConnInterface) RaceControlServiceClient {
+	return &raceControlServiceClient{cc}
+}
+
+func (c *raceControlServiceClient) CreateSession(ctx context.Context, in *SessionConfig, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) StartSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_StartSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) PauseSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_PauseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) ResumeSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_ResumeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) AbortSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_AbortSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) RestartSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_RestartSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) ChangeTrack(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_ChangeTrack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) AddCar(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_AddCar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) RemoveCar(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_RemoveCar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) IssuePenalty(ctx context.Context, in *CarPenalty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_IssuePenalty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) RescindPenalty(ctx context.Context, in *CarRequest, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_RescindPenalty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) GetRaceState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RaceControlState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RaceControlState)
+	err := c.cc.Invoke(ctx, RaceControlService_GetRaceState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raceControlServiceClient) CloseSession(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ControlAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlAck)
+	err := c.cc.Invoke(ctx, RaceControlService_CloseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RaceControlServiceServer is the server API for RaceControlService service.
+// All implementations must embed UnimplementedRaceControlServiceServer
+// for forward compatibility.
+//
+// Race control, for admins only. Every call must carry the admin token in
+// the "x-admin-token" metadata header, and acts on the session named in the
+// "x-session-id" header (the default session if there is none).
+type RaceControlServiceServer interface {
+	// Replace the current session, it stays NOTREADY until started
+	CreateSession(context.Context, *SessionConfig) (*ControlAck, error)
+	StartSession(context.Context, *Empty) (*ControlAck, error)
+	PauseSession(context.Context, *Empty) (*ControlAck, error)
+	ResumeSession(context.Context, *Empty) (*ControlAck, error)
+	AbortSession(context.Context, *Empty) (*ControlAck, error)
+	// Start the current session over with the same setup
+	RestartSession(context.Context, *Empty) (*ControlAck, error)
+	ChangeTrack(context.Context, *TrackRequest) (*ControlAck, error)
+	AddCar(context.Context, *CarRequest) (*ControlAck, error)
+	RemoveCar(context.Context, *CarRequest) (*ControlAck, error)
+	IssuePenalty(context.Context, *CarPenalty) (*ControlAck, error)
+	RescindPenalty(context.Context, *CarRequest) (*ControlAck, error)
+	GetRaceState(context.Context, *Empty) (*RaceControlState, error)
+	// Close a lobby and stop its tick loop, the default session stays
+	CloseSession(context.Context, *Empty) (*ControlAck, error)
+	mustEmbedUnimplementedRaceControlServiceServer()
+}
+
+// UnimplementedRaceControlServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRaceControlServiceServer struct{}
+
+func (UnimplementedRaceControlServiceServer) CreateSession(context.Context, *SessionConfig) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) StartSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) PauseSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method PauseSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) ResumeSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResumeSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) AbortSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method AbortSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) RestartSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestartSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) ChangeTrack(context.Context, *TrackRequest) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeTrack not implemented")
+}
+func (UnimplementedRaceControlServiceServer) AddCar(context.Context, *CarRequest) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddCar not implemented")
+}
+func (UnimplementedRaceControlServiceServer) RemoveCar(context.Context, *CarRequest) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveCar not implemented")
+}
+func (UnimplementedRaceControlServiceServer) IssuePenalty(context.Context, *CarPenalty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method IssuePenalty not implemented")
+}
+func (UnimplementedRaceControlServiceServer) RescindPenalty(context.Context, *CarRequest) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method RescindPenalty not implemented")
+}
+func (UnimplementedRaceControlServiceServer) GetRaceState(context.Context, *Empty) (*RaceControlState, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRaceState not implemented")
+}
+func (UnimplementedRaceControlServiceServer) CloseSession(context.Context, *Empty) (*ControlAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedRaceControlServiceServer) mustEmbedUnimplementedRaceControlServiceServer() {}
+func (UnimplementedRaceControlServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeRaceControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RaceControlServiceServer will
+// result in compilation errors.
+type UnsafeRaceControlServiceServer interface {
+	mustEmbedUnimplementedRaceControlServiceServer()
+}
+
+func RegisterRaceControlServiceServer(s grpc.ServiceRegistrar, srv RaceControlServiceServer) {
+	// If the following call panics, it indicates UnimplementedRaceControlServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RaceControlService_ServiceDesc, srv)
+}
+
+func _RaceControlService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).CreateSession(ctx, req.(*SessionConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_StartSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).StartSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_StartSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).StartSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_PauseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).PauseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_PauseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).PauseSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_ResumeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).ResumeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_ResumeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).ResumeSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_AbortSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).AbortSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_AbortSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).AbortSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_RestartSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).RestartSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_RestartSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).RestartSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_ChangeTrack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).ChangeTrack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_ChangeTrack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).ChangeTrack(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_AddCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).AddCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_AddCar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).AddCar(ctx, req.(*CarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_RemoveCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).RemoveCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_RemoveCar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).RemoveCar(ctx, req.(*CarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_IssuePenalty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CarPenalty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).IssuePenalty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_IssuePenalty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).IssuePenalty(ctx, req.(*CarPenalty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_RescindPenalty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).RescindPenalty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_RescindPenalty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).RescindPenalty(ctx, req.(*CarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_GetRaceState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).GetRaceState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_GetRaceState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).GetRaceState(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RaceControlService_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaceControlServiceServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RaceControlService_CloseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaceControlServiceServer).CloseSession(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RaceControlService_ServiceDesc is the grpc.ServiceDesc for RaceControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RaceControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "car.RaceControlService",
+	HandlerType: (*RaceControlServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSession",
+			Handler:    _RaceControlService_CreateSession_Handler,
+		},
+		{
+			MethodName: "StartSession",
+			Handler:    _RaceControlService_StartSession_Handler,
+		},
+		{
+			MethodName: "PauseSession",
+			Handler:    _RaceControlService_PauseSession_Handler,
+		},
+		{
+			MethodName: "ResumeSession",
+			Handler:    _RaceControlService_ResumeSession_Handler,
+		},
+		{
+			MethodName: "AbortSession",
+			Handler:    _RaceControlService_AbortSession_Handler,
+		},
+		{
+			MethodName: "RestartSession",
+			Handler:    _RaceControlService_RestartSession_Handler,
+		},
+		{
+			MethodName: "ChangeTrack",
+			Handler:    _RaceControlService_ChangeTrack_Handler,
+		},
+		{
+			MethodName: "AddCar",
+			Handler:    _RaceControlService_AddCar_Handler,
+		},
+		{
+			MethodName: "RemoveCar",
+			Handler:    _RaceControlService_RemoveCar_Handler,
+		},
+		{
+			MethodName: "IssuePenalty",
+			Handler:    _RaceControlService_IssuePenalty_Handler,
+		},
+		{
+			MethodName: "RescindPenalty",
+			Handler:    _RaceControlService_RescindPenalty_Handler,
+		},
+		{
+			MethodName: "GetRaceState",
+			Handler:    _RaceControlService_GetRaceState_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _RaceControlService_CloseSession_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "car.proto",
 }
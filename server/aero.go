@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+)
+
+// Work out each car's slipstream and dirty air from where every car is at
+// the start of the tick, so the order cars are moved in makes no difference.
+// The strongest effect from any car ahead applies.
+func (st *RaceState) updateAero() {
+	cosWake := float32(math.Cos(float64(wakeAngle) * math.Pi / 180))
+
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		state.Slipstream = 0
+		state.DirtyAir = 0
+		if !onTrack(state) {
+			continue
+		}
+
+		for _, other := range st.config.carInfos {
+			ahead := st.carStates[other.carId]
+			if other.carId == car.carId || !onTrack(ahead) || ahead.Speed < wakeMinSpeed {
+				continue
+			}
+
+			// The car must sit in the cone behind the car ahead
+			dx := state.Position.X - ahead.Position.X
+			dy := state.Position.Y - ahead.Position.Y
+			dist := float32(math.Hypot(float64(dx), float64(dy)))
+			if dist == 0 || dist >= slipstreamRange {
+				continue
+			}
+			hx, hy := velocity(ahead)
+			behind := -(dx*hx + dy*hy) / (ahead.Speed * dist)
+			if behind < cosWake {
+				continue
+			}
+
+			// Both effects fade out linearly with distance
+			state.Slipstream = max(state.Slipstream, slipstreamDrag*(1-dist/slipstreamRange))
+			if dist < dirtyAirRange {
+				state.DirtyAir = max(state.DirtyAir, dirtyAirGripLoss*(1-dist/dirtyAirRange))
+			}
+		}
+	}
+}
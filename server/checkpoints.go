@@ -0,0 +1,163 @@
+package main
+
+import (
+	"log"
+	"math"
+)
+
+// Lap counting gates are evenly spaced by arc length, gate 0 is the start
+// line. A lap only counts once every gate has been passed in order; gates a
+// car drives back over must be passed again, and a lap with gates skipped
+// does not count, the car has to drive it again.
+
+// Arc length between gates
+func (c *RaceConfig) checkpointSpacing() float32 {
+	return c.geometry.length / float32(c.setup.checkpoints())
+}
+
+// Track progress of every gate
+func (c *RaceConfig) checkpointProgress() []float32 {
+	n := c.setup.checkpoints()
+	progress := make([]float32, n)
+	for i := range n {
+		progress[i] = float32(i) / float32(n)
+	}
+	return progress
+}
+
+// Pass the gates between the car's last position on the track and arc,
+// in the order it drove through them. Moves along the centerline far longer
+// than the car can have driven are shortcuts or jumps between parts of the
+// track that lie close together, they pass no gates.
+func (st *RaceState) updateCheckpoints(state *CarStateExtended, arc, speed float32) {
+	length := st.config.geometry.length
+	spacing := st.config.checkpointSpacing()
+	n := st.config.setup.checkpoints()
+
+	// Signed move along the track, the short way round
+	delta := arc - state.lastArc
+	if delta > length/2 {
+		delta -= length
+	} else if delta < -length/2 {
+		delta += length
+	}
+
+	if float32(math.Abs(float64(delta))) > speed*fixedDt*checkpointSlack+checkpointTolerance {
+		return
+	}
+
+	// Gate k lies at k*spacing, counted on from the last position
+	if delta > 0 {
+		for k := int32(state.lastArc/spacing) + 1; float32(k)*spacing <= state.lastArc+delta; k++ {
+			st.passCheckpoint(state, k%n)
+		}
+	} else if delta < 0 {
+		for k := int32(state.lastArc / spacing); float32(k)*spacing > state.lastArc+delta; k-- {
+			st.reverseCheckpoint(state, (k%n+n)%n)
+		}
+	}
+}
+
+// Count a gate passed going forwards, along with any gates skipped to get
+// there. A gate more than half a lap ahead is one the car has already passed
+// and been thrown back behind, it counts nothing.
+func (st *RaceState) passCheckpoint(state *CarStateExtended, gate int32) {
+	n := st.config.setup.checkpoints()
+	if (gate-state.checkpoints%n+n)%n > n/2 {
+		return
+	}
+
+	for {
+		next := state.checkpoints % n
+		if next != gate {
+			state.missedCheckpoints++
+			state.lapValid = false
+			state.lapCut = true
+			log.Printf("Car %s missed checkpoint %d", state.CarId, next)
+		}
+
+		state.checkpoints++
+		if next == 0 {
+			st.crossLine(state)
+		}
+		if next == gate {
+			return
+		}
+	}
+}
+
+// Take back the last gate passed when the car reverses over it
+func (st *RaceState) reverseCheckpoint(state *CarStateExtended, gate int32) {
+	n := st.config.setup.checkpoints()
+	if state.checkpoints > 0 && (state.checkpoints-1)%n == gate {
+		state.checkpoints--
+	}
+}
+
+// The car has passed the start line in order. The grid is behind the line,
+// so the first crossing only starts the timing; after that each crossing on
+// to a new lap completes one. Crossing again after reversing over the line
+// counts nothing.
+func (st *RaceState) crossLine(state *CarStateExtended) {
+	if !state.crossedFinish {
+		state.crossedFinish = true
+		state.lapCut = false
+		state.sectorStartTick = st.gameTick
+		return
+	}
+
+	n := st.config.setup.checkpoints()
+	if (state.checkpoints-1)/n > state.Lap {
+		if state.lapCut {
+			st.discardLap(state)
+		} else {
+			st.completeLap(state)
+		}
+	}
+}
+
+// Start a cut lap over without counting it: the gates and the distance it
+// covered are taken back, so race order and gaps treat the car as still on it
+func (st *RaceState) discardLap(state *CarStateExtended) {
+	state.checkpoints -= st.config.setup.checkpoints()
+
+	n := int32(len(st.config.centerline))
+	state.distance -= n
+	state.covered -= n
+	if state.covered >= 0 {
+		// Points are recorded again as the car drives the lap again. The
+		// lap they go into is shared with the previous state, so copy it.
+		lap := state.covered / n
+		state.timingLine = state.timingLine[:lap+1]
+		state.timingLine[lap] = append([]int32(nil), state.timingLine[lap]...)
+	}
+
+	state.sectorTimes = nil
+	state.sector = 0
+	state.sectorStartTick = st.gameTick
+	state.lapStartTick = st.gameTick
+	state.lapValid = true
+	state.lapCut = false
+	log.Printf("Car %s skipped checkpoints, lap %d does not count", state.CarId, state.Lap+1)
+}
+
+// Flag a car that keeps facing against the direction of the track
+func (st *RaceState) checkWrongWay(state *CarStateExtended, segment int32) {
+	dx, dy := st.config.geometry.direction(segment)
+	hx, hy := velocity(state)
+
+	if state.Speed < wrongWayMinSpeed || hx*dx+hy*dy >= 0 {
+		if state.WrongWay {
+			log.Printf("Car %s is back on its way", state.CarId)
+		}
+		state.wrongWayTicks = 0
+		state.WrongWay = false
+		return
+	}
+
+	state.wrongWayTicks++
+	if !state.WrongWay && state.wrongWayTicks >= ticks(wrongWayTime) {
+		state.WrongWay = true
+		log.Printf("Car %s is driving the wrong way", state.CarId)
+	}
+}
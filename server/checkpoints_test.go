@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	pb "server/proto"
+)
+
+func TestSkippedGateLapDoesNotCount(t *testing.T) {
+	setup := raceSetup{Seed: 1, RaceType: pb.RaceType_RACEBYLAPS, NumCars: 1, Laps: 5}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	config, err := newRaceConfig(setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := newRaceState(config)
+	state := st.carStates["A"]
+	n := setup.checkpoints()
+
+	// Round to the line, passing every gate but skip
+	lap := func(skip int32) {
+		for gate := int32(1); gate <= n; gate++ {
+			if gate != skip {
+				st.gameTick += 100
+				st.passCheckpoint(state, gate%n)
+			}
+		}
+	}
+
+	st.passCheckpoint(state, 0) // off the grid over the line
+	lap(-1)
+	if state.Lap != 1 || state.missedCheckpoints != 0 {
+		t.Fatalf("lap %d with %d missed after a clean lap, want 1 and 0", state.Lap, state.missedCheckpoints)
+	}
+
+	lap(n / 2)
+	if state.Lap != 1 || state.missedCheckpoints != 1 {
+		t.Errorf("lap %d with %d missed after cutting a gate, want 1 and 1", state.Lap, state.missedCheckpoints)
+	}
+	if len(state.lapTimes) != 1 {
+		t.Errorf("%d lap times, the cut lap timed", len(state.lapTimes))
+	}
+
+	lap(-1)
+	if state.Lap != 2 || !state.lapValid {
+		t.Errorf("lap %d after driving the lap again, want 2", state.Lap)
+	}
+	if want := float32(100*n) * fixedDt; state.lapTimes[1] != want {
+		t.Errorf("lap time %v, want the clean lap only", state.lapTimes[1])
+	}
+}
@@ -0,0 +1,273 @@
+// trackctl converts, transforms and checks track files, through the same
+// loader the server races on. Input may be CSV, GeoJSON, SVG or OSM XML,
+// output is CSV.
+//
+//	trackctl convert [flags] in.csv out.csv
+//	trackctl validate file.csv...
+//	trackctl info file.csv...
+//	trackctl generate [flags] out.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"server/trackdata"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("trackctl: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "convert":
+		err = convert(os.Args[2:])
+	case "validate":
+		err = validate(os.Args[2:])
+	case "info":
+		err = info(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: trackctl convert [flags] in.csv out.csv")
+	fmt.Fprintln(os.Stderr, "       trackctl validate [-lane] file.csv...")
+	fmt.Fprintln(os.Stderr, "       trackctl info [-lane] file.csv...")
+	fmt.Fprintln(os.Stderr, "       trackctl generate [flags] out.csv")
+	fmt.Fprintln(os.Stderr, "run trackctl convert -h or generate -h for their flags")
+	os.Exit(2)
+}
+
+// Read a track file and prepare it as the server would
+func load(filename string, lane bool) (*trackdata.Track, error) {
+	track, err := trackdata.Load(filename, !lane)
+	if err != nil {
+		return nil, err
+	}
+	return track, track.Prepare()
+}
+
+// Read a track, transform it and write it out, then read the output back
+// to check it
+func convert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := flags.String("format", "csv", "output format: csv or tum")
+	lane := flags.Bool("lane", false, "the file is a pit lane, open from entry to exit")
+	id := flags.String("id", "", "track id, default the input's")
+	name := flags.String("name", "", "track name, default the input's")
+	country := flags.String("country", "", "country, default the input's")
+	mirror := flags.Bool("mirror", false, "reflect across the y axis, which also turns the direction round")
+	reverse := flags.Bool("reverse", false, "drive the other way from the same start/finish line")
+	rotate := flags.Float64("rotate", 0, "degrees to turn anticlockwise about the origin")
+	scale := flags.Float64("scale", 1, "scale about the origin, widths included")
+	translate := flags.String("translate", "", "x,y or x,y,z in metres to move the track by")
+	start := flags.Int("start", 0, "point to put the start/finish line on, after the other changes")
+	startNear := flags.String("start-near", "", "x,y to put the start/finish line nearest to, instead of -start")
+	spacing := flags.Float64("spacing", 0, "resample to points this many metres apart")
+	smoothing := flags.Int("smooth", 0, "smoothing passes over the centerline")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("convert needs an input and an output file")
+	}
+	in, out := flags.Arg(0), flags.Arg(1)
+
+	outFormat, err := trackdata.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+	offset, err := parseCoords(*translate, 3)
+	if err != nil {
+		return fmt.Errorf("-translate: %v", err)
+	}
+
+	track, err := load(in, *lane)
+	if err != nil {
+		return err
+	}
+
+	if *id != "" {
+		track.Meta.ID = *id
+	}
+	if *name != "" {
+		track.Meta.Name = *name
+	}
+	if *country != "" {
+		track.Meta.Country = *country
+	}
+
+	if *mirror {
+		track.Mirror()
+	}
+	if *reverse {
+		track.Reverse()
+	}
+	if *rotate != 0 {
+		track.Rotate(float32(*rotate))
+	}
+	if *scale != 1 {
+		if *scale <= 0 {
+			return fmt.Errorf("-scale must be positive")
+		}
+		track.Scale(float32(*scale))
+	}
+	track.Translate(offset[0], offset[1], offset[2])
+
+	if *spacing > 0 {
+		track.Resample(float32(*spacing))
+	}
+	track.Smooth(*smoothing)
+
+	if *startNear != "" {
+		near, err := parseCoords(*startNear, 2)
+		if err != nil {
+			return fmt.Errorf("-start-near: %v", err)
+		}
+		*start = track.Nearest(near[0], near[1])
+	}
+	if err := track.StartAt(*start); err != nil {
+		return err
+	}
+
+	if err := track.Validate(); err != nil {
+		return err
+	}
+	if err := track.Save(out, outFormat); err != nil {
+		return err
+	}
+
+	// What was written must load the same way
+	written, err := load(out, *lane)
+	if err != nil {
+		return fmt.Errorf("written track does not load: %v", err)
+	}
+	log.Printf("%s: %d points, %.0f m", out, len(written.Points), written.Length())
+	return nil
+}
+
+// Check track files, reporting every bad one
+func validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	lane := flags.Bool("lane", false, "the files are pit lanes")
+	flags.Parse(args)
+
+	failed := 0
+	for _, filename := range flags.Args() {
+		if _, err := load(filename, *lane); err != nil {
+			log.Print(err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: ok\n", filename)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, flags.NArg())
+	}
+	return nil
+}
+
+// Print what the server would make of track files
+func info(args []string) error {
+	flags := flag.NewFlagSet("info", flag.ExitOnError)
+	lane := flags.Bool("lane", false, "the files are pit lanes")
+	flags.Parse(args)
+
+	for _, filename := range flags.Args() {
+		track, err := load(filename, *lane)
+		if err != nil {
+			return err
+		}
+
+		meta := track.Meta
+		fmt.Printf("%s\n", filename)
+		fmt.Printf("  id:        %s\n", meta.ID)
+		fmt.Printf("  name:      %s\n", meta.Name)
+		fmt.Printf("  country:   %s\n", meta.Country)
+		fmt.Printf("  points:    %d\n", len(track.Points))
+		fmt.Printf("  length:    %.1f m\n", track.Length())
+		if !*lane {
+			fmt.Printf("  direction: %v\n", track.Direction())
+		}
+		start := track.Points[0]
+		fmt.Printf("  start:     %.2f,%.2f\n", start.X, start.Y)
+	}
+	return nil
+}
+
+// Write out a generated track, as a session asking for one would race on
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	format := flags.String("format", "csv", "output format: csv or tum")
+	seed := flags.Uint64("seed", 1, "layout seed")
+	length := flags.Float64("length", 0, "length in metres, default 4000")
+	corners := flags.Int("corners", 0, "number of corners, default 12")
+	width := flags.Float64("width", 0, "average width in metres, default 12")
+	variation := flags.Float64("width-variation", -1, "fraction the width varies either way, negative for the default 0.2")
+	difficulty := flags.Float64("difficulty", -1, "0 flowing to 1 tight and twisty, negative for the default 0.5")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("generate needs an output file")
+	}
+	outFormat, err := trackdata.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	track, err := trackdata.Generate(trackdata.Generator{
+		Seed:           *seed,
+		Length:         float32(*length),
+		Corners:        *corners,
+		Width:          float32(*width),
+		WidthVariation: float32(*variation),
+		Difficulty:     float32(*difficulty),
+	})
+	if err != nil {
+		return err
+	}
+	if err := track.Save(flags.Arg(0), outFormat); err != nil {
+		return err
+	}
+	log.Printf("%s: %d points, %.0f m, %v", flags.Arg(0), len(track.Points), track.Length(), track.Direction())
+	return nil
+}
+
+// Comma-separated numbers, at most n of them, the rest zero
+func parseCoords(value string, n int) ([]float32, error) {
+	coords := make([]float32, n)
+	if value == "" {
+		return coords, nil
+	}
+
+	parts := strings.Split(value, ",")
+	if len(parts) > n {
+		return nil, fmt.Errorf("expected at most %d numbers, got %q", n, value)
+	}
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		coords[i] = float32(v)
+	}
+	return coords, nil
+}
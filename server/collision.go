@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	pb "server/proto"
+)
+
+// Resolve car-to-car contacts after all cars have moved this tick.
+// Each car is a circle of carCollisionRadius; overlaps are pushed apart and
+// an impulse is exchanged along the contact normal, weighted by car weight.
+func (st *RaceState) resolveCollisions() {
+	st.contacts = st.contacts[:0]
+
+	for i := 0; i < len(st.config.carInfos); i++ {
+		for j := i + 1; j < len(st.config.carInfos); j++ {
+			a := st.carStates[st.config.carInfos[i].carId]
+			b := st.carStates[st.config.carInfos[j].carId]
+			if !onTrack(a) || !onTrack(b) {
+				continue
+			}
+
+			if contact := st.collide(a, b, a.mass(st.config.carInfos[i]), b.mass(st.config.carInfos[j])); contact != nil {
+				st.contacts = append(st.contacts, contact)
+			}
+		}
+	}
+}
+
+// Collision response between two cars, returns nil when they do not touch
+func (st *RaceState) collide(a, b *CarStateExtended, massA, massB float32) *pb.ContactEvent {
+	dx := b.Position.X - a.Position.X
+	dy := b.Position.Y - a.Position.Y
+	dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if dist >= 2*carCollisionRadius || dist == 0 {
+		return nil
+	}
+
+	// Contact normal from a to b
+	nx := dx / dist
+	ny := dy / dist
+
+	// Push the cars apart, the lighter car moves further
+	overlap := 2*carCollisionRadius - dist
+	totalMass := massA + massB
+	a.Position.X -= nx * overlap * massB / totalMass
+	a.Position.Y -= ny * overlap * massB / totalMass
+	b.Position.X += nx * overlap * massA / totalMass
+	b.Position.Y += ny * overlap * massA / totalMass
+
+	vax, vay := velocity(a)
+	vbx, vby := velocity(b)
+
+	// Closing speed of each car along the normal, used for blame
+	approachA := vax*nx + vay*ny
+	approachB := -(vbx*nx + vby*ny)
+
+	contact := &pb.ContactEvent{
+		CarA:     a.CarId,
+		CarB:     b.CarId,
+		GameTick: st.gameTick,
+		Position: &pb.Point3D{
+			X: (a.Position.X + b.Position.X) / 2,
+			Y: (a.Position.Y + b.Position.Y) / 2,
+			Z: (a.Position.Z + b.Position.Z) / 2,
+		},
+	}
+
+	// Only exchange momentum when the cars are moving into each other
+	relative := (vbx-vax)*nx + (vby-vay)*ny
+	if relative >= 0 {
+		return contact
+	}
+
+	impulse := -(1 + collisionRestitution) * relative / (1/massA + 1/massB)
+	vax -= impulse / massA * nx
+	vay -= impulse / massA * ny
+	vbx += impulse / massB * nx
+	vby += impulse / massB * ny
+
+	setVelocity(a, vax, vay)
+	setVelocity(b, vbx, vby)
+
+	contact.Impulse = impulse
+	st.stewardContact(contact, a, b, approachA, approachB)
+
+	return contact
+}
+
+// Cars waiting in the garage or finished take no part in contacts
+func onTrack(state *CarStateExtended) bool {
+	return state.Status == pb.CarStatus_RACING || state.Status == pb.CarStatus_SERVINGPENALTY ||
+		state.Status == pb.CarStatus_INPITS
+}
+
+// Velocity vector from heading and speed
+func velocity(state *CarStateExtended) (float32, float32) {
+	rad := float64(state.Heading) * math.Pi / 180
+	return float32(math.Cos(rad)) * state.Speed, float32(math.Sin(rad)) * state.Speed
+}
+
+// Apply a velocity after contact: the forward part becomes the new speed
+// and the sideways part yaws the car towards it
+func setVelocity(state *CarStateExtended, vx, vy float32) {
+	rad := float64(state.Heading) * math.Pi / 180
+	hx := float32(math.Cos(rad))
+	hy := float32(math.Sin(rad))
+
+	forward := vx*hx + vy*hy
+	lateral := vy*hx - vx*hy
+	if forward < 0 {
+		forward = 0
+	}
+
+	yaw := float32(math.Atan2(float64(lateral), math.Max(float64(forward), 1))) * 180 / math.Pi
+	state.Speed = forward
+	state.Heading += yaw * collisionYawFactor
+	for state.Heading < 0 {
+		state.Heading += 360
+	}
+	for state.Heading >= 360 {
+		state.Heading -= 360
+	}
+}
+
+// Stewarding rule for contacts: a hard hit is the fault of the car that
+// drove into the other one
+func (st *RaceState) stewardContact(contact *pb.ContactEvent, a, b *CarStateExtended, approachA, approachB float32) {
+	if contact.Impulse < collisionPenaltyImpulse {
+		return
+	}
+
+	atFault, victim := a, b
+	if approachB > approachA {
+		atFault, victim = b, a
+	}
+
+	contact.AtFault = atFault.CarId
+	st.issuePenalty(atFault, fmt.Sprintf("causing a collision with car %s", victim.CarId), collisionPenalty)
+}
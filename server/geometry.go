@@ -0,0 +1,251 @@
+package main
+
+import (
+	"math"
+
+	pb "server/proto"
+)
+
+// Precomputed centerline segments for fast nearest-point queries. Segment i
+// runs from point i to point i+1, the last one closes the loop unless the
+// geometry is an open lane such as the pit lane.
+type trackGeometry struct {
+	points   []TrackPoint
+	open     bool
+	arcStart []float32 // arc length at the start of each segment
+	length   float32
+
+	// Uniform grid of the segments overlapping each cell
+	minX, minY float32
+	cols, rows int
+	cells      [][]int32
+}
+
+// Where a position projects onto the centerline
+type trackLocation struct {
+	segment int32
+	t       float32 // 0-1 along the segment
+	arc     float32 // arc length from the start line
+	lateral float32 // signed offset, positive to the left of the direction of travel
+	dist2   float32 // squared distance to the centerline
+}
+
+func newTrackGeometry(points []TrackPoint) *trackGeometry {
+	return buildGeometry(points, false)
+}
+
+// Geometry of a lane from its first point to its last
+func newLaneGeometry(points []TrackPoint) *trackGeometry {
+	return buildGeometry(points, true)
+}
+
+func buildGeometry(points []TrackPoint, open bool) *trackGeometry {
+	n := len(points)
+	g := &trackGeometry{
+		points:   points,
+		open:     open,
+		arcStart: make([]float32, n+1),
+	}
+
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := -minX, -minY
+	for i, point := range points {
+		next := points[(i+1)%n]
+		g.arcStart[i+1] = g.arcStart[i] + float32(math.Hypot(
+			float64(next.centerX-point.centerX), float64(next.centerY-point.centerY)))
+
+		minX, maxX = min(minX, point.centerX), max(maxX, point.centerX)
+		minY, maxY = min(minY, point.centerY), max(maxY, point.centerY)
+	}
+	g.length = g.arcStart[g.segments()]
+
+	g.minX, g.minY = minX, minY
+	g.cols = int((maxX-minX)/geometryCellSize) + 1
+	g.rows = int((maxY-minY)/geometryCellSize) + 1
+	g.cells = make([][]int32, g.cols*g.rows)
+
+	// Every segment goes in each cell its bounding box touches
+	for i := range g.segments() {
+		point := points[i]
+		next := points[(i+1)%n]
+		col0, row0 := g.cell(min(point.centerX, next.centerX), min(point.centerY, next.centerY))
+		col1, row1 := g.cell(max(point.centerX, next.centerX), max(point.centerY, next.centerY))
+		for row := row0; row <= row1; row++ {
+			for col := col0; col <= col1; col++ {
+				g.cells[row*g.cols+col] = append(g.cells[row*g.cols+col], int32(i))
+			}
+		}
+	}
+
+	return g
+}
+
+// Number of segments, one fewer than points for an open lane
+func (g *trackGeometry) segments() int {
+	if g.open {
+		return len(g.points) - 1
+	}
+	return len(g.points)
+}
+
+// Grid cell of a position, clamped to the grid
+func (g *trackGeometry) cell(x, y float32) (int, int) {
+	col := min(max(int((x-g.minX)/geometryCellSize), 0), g.cols-1)
+	row := min(max(int((y-g.minY)/geometryCellSize), 0), g.rows-1)
+	return col, row
+}
+
+// Project a position onto one segment
+func (g *trackGeometry) project(pos *pb.Point3D, segment int32) trackLocation {
+	n := int32(len(g.points))
+	a := g.points[segment]
+	b := g.points[(segment+1)%n]
+
+	dx := b.centerX - a.centerX
+	dy := b.centerY - a.centerY
+	offX := pos.X - a.centerX
+	offY := pos.Y - a.centerY
+
+	t := float32(0)
+	if length2 := dx*dx + dy*dy; length2 > 0 {
+		t = min(max((offX*dx+offY*dy)/length2, 0), 1)
+	}
+
+	nearX := offX - t*dx
+	nearY := offY - t*dy
+	lateral := float32(math.Hypot(float64(nearX), float64(nearY)))
+	if dx*offY-dy*offX < 0 {
+		lateral = -lateral
+	}
+
+	// The end of the last segment is the start line again
+	arc := g.arcStart[segment] + t*(g.arcStart[segment+1]-g.arcStart[segment])
+	if arc >= g.length && !g.open {
+		arc -= g.length
+	}
+
+	return trackLocation{
+		segment: segment,
+		t:       t,
+		arc:     arc,
+		lateral: lateral,
+		dist2:   nearX*nearX + nearY*nearY,
+	}
+}
+
+// Nearest point on the centerline. With a hint (the car's segment on the last
+// tick, -1 for none) only nearby segments are searched, falling back to the
+// grid when the car is no longer near them.
+func (g *trackGeometry) locate(pos *pb.Point3D, hint int32) trackLocation {
+	n := int32(len(g.points))
+
+	if hint >= 0 && hint < n {
+		best := g.project(pos, hint)
+		bestOffset := int32(0)
+		for offset := -geometryWindow; offset <= geometryWindow; offset++ {
+			segment := (hint + offset + n) % n
+			if g.open && (hint+offset < 0 || hint+offset >= n-1) {
+				continue
+			}
+			loc := g.project(pos, segment)
+			if loc.dist2 < best.dist2 {
+				best, bestOffset = loc, offset
+			}
+		}
+
+		// A minimum inside the window and close to the track is the answer
+		edge := bestOffset == -geometryWindow || bestOffset == geometryWindow
+		if !edge && best.dist2 <= geometryCellSize*geometryCellSize {
+			return best
+		}
+	}
+
+	return g.nearest(pos)
+}
+
+// Nearest point on the centerline, searching the grid in growing rings of
+// cells until no unsearched cell can hold anything closer
+func (g *trackGeometry) nearest(pos *pb.Point3D) trackLocation {
+	best := trackLocation{segment: -1, dist2: math.MaxFloat32}
+	col, row := g.cell(pos.X, pos.Y)
+
+	for ring := 0; ring <= max(g.cols, g.rows); ring++ {
+		for r := row - ring; r <= row+ring; r++ {
+			for c := col - ring; c <= col+ring; c++ {
+				onRing := r == row-ring || r == row+ring || c == col-ring || c == col+ring
+				if !onRing || r < 0 || r >= g.rows || c < 0 || c >= g.cols {
+					continue
+				}
+				for _, segment := range g.cells[r*g.cols+c] {
+					if loc := g.project(pos, segment); loc.dist2 < best.dist2 {
+						best = loc
+					}
+				}
+			}
+		}
+
+		// Cells further out are at least ring cells away
+		reach := float32(ring) * geometryCellSize
+		if best.segment >= 0 && best.dist2 <= reach*reach {
+			break
+		}
+	}
+
+	return best
+}
+
+// Distance from the located position to the nearest track edge, positive when
+// outside the track
+func (g *trackGeometry) edgeExcess(loc trackLocation) float32 {
+	n := int32(len(g.points))
+	a := g.points[loc.segment]
+	b := g.points[(loc.segment+1)%n]
+
+	if loc.lateral >= 0 {
+		return loc.lateral - (a.widthLeft + loc.t*(b.widthLeft-a.widthLeft))
+	}
+	return -loc.lateral - (a.widthRight + loc.t*(b.widthRight-a.widthRight))
+}
+
+// Unit direction of travel along a segment
+func (g *trackGeometry) direction(segment int32) (float32, float32) {
+	n := int32(len(g.points))
+	a := g.points[segment]
+	b := g.points[(segment+1)%n]
+
+	dx := b.centerX - a.centerX
+	dy := b.centerY - a.centerY
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return 0, 0
+	}
+	return dx / length, dy / length
+}
+
+// Rise over run along a segment, in the direction of travel
+func (g *trackGeometry) gradient(segment int32) float32 {
+	n := int32(len(g.points))
+	run := g.arcStart[segment+1] - g.arcStart[segment]
+	if run == 0 {
+		return 0
+	}
+	return (g.points[(segment+1)%n].centerZ - g.points[segment].centerZ) / run
+}
+
+// Banking of a segment in degrees, the mean of its ends
+func (g *trackGeometry) bankingAt(segment int32) float32 {
+	n := int32(len(g.points))
+	return (g.points[segment].banking + g.points[(segment+1)%n].banking) / 2
+}
+
+// Height of the track surface at a located position, carrying the banking
+// on past the edges
+func (g *trackGeometry) heightAt(loc trackLocation) float32 {
+	n := int32(len(g.points))
+	a := g.points[loc.segment]
+	b := g.points[(loc.segment+1)%n]
+
+	centerZ := a.centerZ + loc.t*(b.centerZ-a.centerZ)
+	banking := a.banking + loc.t*(b.banking-a.banking)
+	return centerZ + loc.lateral*float32(math.Tan(float64(banking)*math.Pi/180))
+}
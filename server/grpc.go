@@ -2,11 +2,20 @@ package main
 
 import (
 	"context"
+	"io"
 	pb "server/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Stream race updates
-func (s *CarServer) StreamRaceUpdates(req *pb.Empty, stream pb.CarService_StreamRaceUpdatesServer) error {
+func (c *CarServer) StreamRaceUpdates(req *pb.SessionRequest, stream pb.CarService_StreamRaceUpdatesServer) error {
+	s, err := c.session(req.GetSessionId())
+	if err != nil {
+		return err
+	}
+
 	clientChan := make(chan *pb.RaceUpdate, 10)
 
 	s.mu.Lock()
@@ -21,80 +30,218 @@ func (s *CarServer) StreamRaceUpdates(req *pb.Empty, stream pb.CarService_Stream
 		s.mu.Unlock()
 	}()
 
-	for update := range clientChan {
-		if err := stream.Send(update); err != nil {
-			return err
+	for {
+		select {
+		case update := <-clientChan:
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case <-s.done:
+			return status.Error(codes.Unavailable, "session closed")
 		}
 	}
-	return nil
 }
 
-func (s *CarServer) createRaceUpdate() *pb.RaceUpdate {
-	states := make([]*pb.CarState, 0, len(s.carStates))
-	for _, state := range s.carStates {
-		states = append(states, &pb.CarState{
-			CarId:  state.CarId,
-			Status: state.Status,
-			Position: &pb.Point3D{
-				X: state.Position.X,
-				Y: state.Position.Y,
-				Z: state.Position.Z,
-			},
-			Heading: state.Heading,
-			Speed:   state.Speed,
-			Lap:     state.Lap,
-		})
+func (st *RaceState) createRaceUpdate() *pb.RaceUpdate {
+	states := make([]*pb.CarState, 0, len(st.carStates))
+	for _, state := range st.carStates {
+		states = append(states, carStateUpdate(state))
 	}
 
-	penalties := make([]*pb.CarPenalty, 0, len(s.penalties))
-	for _, penalty := range s.penalties {
+	penalties := make([]*pb.CarPenalty, 0, len(st.penalties))
+	for _, penalty := range st.penalties {
 		penalties = append(penalties, &pb.CarPenalty{
 			CarId:            penalty.CarId,
 			Reason:           penalty.Reason,
 			GameTick:         penalty.GameTick,
 			RemainingPenalty: penalty.RemainingPenalty,
+			Kind:             penalty.Kind,
+		})
+	}
+
+	contacts := make([]*pb.ContactEvent, 0, len(st.contacts))
+	for _, contact := range st.contacts {
+		contacts = append(contacts, &pb.ContactEvent{
+			CarA:     contact.CarA,
+			CarB:     contact.CarB,
+			GameTick: contact.GameTick,
+			Impulse:  contact.Impulse,
+			Position: &pb.Point3D{
+				X: contact.Position.X,
+				Y: contact.Position.Y,
+				Z: contact.Position.Z,
+			},
+			AtFault: contact.AtFault,
 		})
 	}
 
 	// Calculate intervals
-	toLeader, forPosition := s.calculateIntervals()
+	toLeader, forPosition := st.calculateIntervals()
 
 	return &pb.RaceUpdate{
 		RaceStatus: &pb.RaceStatus{
-			Status:    s.raceStatus.Status,
-			TotalLaps: s.raceStatus.TotalLaps,
-			GameTick:  s.raceStatus.GameTick,
+			Status:            st.statusText(),
+			TotalLaps:         st.config.setup.Laps,
+			GameTick:          st.gameTick,
+			RaceType:          st.config.setup.RaceType,
+			TimeLeft:          st.raceTimeLeft,
+			Phase:             st.phase,
+			LightsOn:          st.lightsOn,
+			MandatoryPitStops: st.config.setup.MandatoryStops,
 		},
 		Cars:        states,
 		Penalties:   penalties,
 		ToLeader:    toLeader,
 		ForPosition: forPosition,
-		GameTick:    s.gameTick,
+		Contacts:    contacts,
+		Timing:      st.timingInfo(),
+		GameTick:    st.gameTick,
+	}
+}
+
+// Public state of one car
+func carStateUpdate(state *CarStateExtended) *pb.CarState {
+	return &pb.CarState{
+		CarId:  state.CarId,
+		Status: state.Status,
+		Position: &pb.Point3D{
+			X: state.Position.X,
+			Y: state.Position.Y,
+			Z: state.Position.Z,
+		},
+		Heading:     state.Heading,
+		Speed:       state.Speed,
+		Lap:         state.Lap,
+		WrongWay:    state.WrongWay,
+		PitStops:    state.PitStops,
+		PitStopLeft: state.PitStopLeft,
+		TyreWear:    state.TyreWear,
+		Fuel:        state.Fuel,
+		Slipstream:  state.Slipstream,
+		DirtyAir:    state.DirtyAir,
+		Surface:     state.Surface,
 	}
 }
 
 // Unary RPC for per-frame input
-func (s *CarServer) SendPlayerInput(ctx context.Context, input *pb.PlayerInput) (*pb.InputAck, error) {
+func (c *CarServer) SendPlayerInput(ctx context.Context, input *pb.PlayerInput) (*pb.InputAck, error) {
+	s, err := c.session(input.GetSessionId())
+	if err != nil {
+		return &pb.InputAck{
+			Accepted: false,
+			Reason:   "unknown session",
+		}, nil
+	}
+
 	carId := input.GetCarId()
 	if !s.validateToken(carId, input.GetAuthToken()) {
 		return &pb.InputAck{
 			Accepted: false,
 			Reason:   "invalid token",
-			GameLoop: s.gameTick,
+			GameLoop: s.currentTick(),
 		}, nil
 	}
 
 	s.mu.Lock()
-	s.playerInput[carId] = &PlayerInput{
-		steering:  input.GetSteering(),
-		throttle:  input.GetThrottle(),
-		brake:     input.GetBrake(),
-		timestamp: input.GetTimestamp(),
-	}
+	accepted := s.setInput(carId, input)
+	gameTick := s.state.gameTick
+	sequence := s.carInputs(carId).sequence
 	s.mu.Unlock()
 
+	if !accepted {
+		return &pb.InputAck{
+			Accepted: false,
+			Reason:   "stale input",
+			GameLoop: gameTick,
+			Sequence: sequence,
+		}, nil
+	}
+	s.signalInput()
+
 	return &pb.InputAck{
 		Accepted: true,
-		GameLoop: s.gameTick,
+		GameLoop: gameTick,
+		Sequence: sequence,
 	}, nil
 }
+
+// Streaming RPC for per-frame input. The first input authenticates the
+// stream, after that inputs are taken as they come and every tick is
+// answered with the car's state until either side ends the stream.
+func (c *CarServer) Drive(stream pb.CarService_DriveServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	s, err := c.session(first.GetSessionId())
+	if err != nil {
+		return err
+	}
+	carId := first.GetCarId()
+	if !s.validateToken(carId, first.GetAuthToken()) {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	updates := make(chan *pb.DriveUpdate, 10)
+
+	s.mu.Lock()
+	s.drivers[updates] = carId
+	s.resetSequence(carId)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.drivers, updates)
+		s.mu.Unlock()
+	}()
+
+	// Inputs are read on their own so acks go out while waiting for them
+	received := make(chan error, 1)
+	go func() {
+		input := first
+		for {
+			if err := s.driveInput(carId, input); err != nil {
+				received <- err
+				return
+			}
+			if input, err = stream.Recv(); err != nil {
+				received <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case update := <-updates:
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case err := <-received:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-s.done:
+			return status.Error(codes.Unavailable, "session closed")
+		}
+	}
+}
+
+// Take an input from a Drive stream, as long as the car is still in the
+// session. Stale inputs are dropped, the acks show which input is in use.
+func (s *RaceSession) driveInput(carId string, input *pb.PlayerInput) error {
+	s.mu.Lock()
+	if _, ok := s.authTokens[carId]; !ok {
+		s.mu.Unlock()
+		return status.Errorf(codes.NotFound, "car %q has left the session", carId)
+	}
+	accepted := s.setInput(carId, input)
+	s.mu.Unlock()
+
+	if accepted {
+		s.signalInput()
+	}
+	return nil
+}
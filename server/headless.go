@@ -0,0 +1,140 @@
+package main
+
+import (
+	"time"
+
+	pb "server/proto"
+)
+
+// Start the driver for the configured simulation mode
+func (s *RaceSession) run() {
+	switch s.mode {
+	case pb.SimulationMode_FAST:
+		s.fastLoop()
+	case pb.SimulationMode_LOCKSTEP:
+		s.lockstepLoop()
+	default:
+		s.physicsLoop()
+	}
+}
+
+// Headless driver: steps as fast as the CPU allows, once a car has checked in
+func (s *RaceSession) fastLoop() {
+	for !s.closed() {
+		s.idleWhilePaused()
+		if !s.waitForCars() {
+			return
+		}
+
+		s.mu.Lock()
+		s.tick()
+		s.mu.Unlock()
+	}
+}
+
+// Headless driver: steps as soon as every checked-in car has sent its
+// input for the next tick
+func (s *RaceSession) lockstepLoop() {
+	for !s.closed() {
+		s.idleWhilePaused()
+		if !s.waitForInputs() {
+			return
+		}
+
+		s.mu.Lock()
+		s.tick()
+		s.mu.Unlock()
+	}
+}
+
+// Headless drivers fall back to the real-time rate while the race is paused
+func (s *RaceSession) idleWhilePaused() {
+	s.mu.RLock()
+	paused := s.paused
+	s.mu.RUnlock()
+
+	if paused {
+		time.Sleep(updateRate)
+	}
+}
+
+// Block until a car has checked in or race control has an action to run,
+// false if the session closed
+func (s *RaceSession) waitForCars() bool {
+	for {
+		s.mu.RLock()
+		ready := len(s.checkedIn) > 0 || len(s.commands) > 0
+		s.mu.RUnlock()
+
+		if ready {
+			return true
+		}
+		select {
+		case <-s.inputArrived:
+		case <-s.done:
+			return false
+		}
+	}
+}
+
+// Block until all inputs for the next tick are in. A car that stops sending
+// only holds up the race for lockstepTimeout. False if the session closed.
+func (s *RaceSession) waitForInputs() bool {
+	timeout := time.NewTimer(lockstepTimeout)
+	defer timeout.Stop()
+
+	for {
+		s.mu.RLock()
+		waiting := len(s.checkedIn) == 0
+		// Race control actions do not wait for the cars
+		ready := len(s.commands) > 0 || !waiting && s.inputsComplete()
+		s.mu.RUnlock()
+
+		if ready {
+			return true
+		}
+
+		// Nothing to train until a car checks in
+		if waiting {
+			select {
+			case <-s.inputArrived:
+			case <-s.done:
+				return false
+			}
+			continue
+		}
+
+		select {
+		case <-s.inputArrived:
+		case <-timeout.C:
+			return true
+		case <-s.done:
+			return false
+		}
+	}
+}
+
+// Whether every checked-in car has sent input since the last tick, caller holds s.mu
+func (s *RaceSession) inputsComplete() bool {
+	for carId := range s.checkedIn {
+		if s.inputTick[carId] <= s.state.gameTick {
+			return false
+		}
+	}
+	return true
+}
+
+// Wake a waiting headless driver without blocking the caller
+func (s *RaceSession) signalInput() {
+	select {
+	case s.inputArrived <- struct{}{}:
+	default:
+	}
+}
+
+// Start the next headless race on the same track with the next seed, caller holds s.mu
+func (s *RaceSession) restartRace() {
+	setup := s.config.setup
+	setup.Seed++
+	s.startNextRace(setup)
+}
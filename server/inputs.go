@@ -0,0 +1,159 @@
+package main
+
+import (
+	"time"
+
+	pb "server/proto"
+)
+
+// When the update for a tick went out
+type sentUpdate struct {
+	tick int32
+	at   time.Time
+}
+
+// Ordering and timing of one car's inputs over a race
+type carInputs struct {
+	sequence uint64 // of the input in use
+	target   int32  // tick the input in use was for, 0 if it named none
+
+	received    int32
+	stale       int32
+	future      int32
+	late        int32
+	tagged      int32 // inputs naming a target tick
+	ticksLate   int32 // summed over the tagged inputs
+	maxLate     int32
+	responses   int32 // inputs answering an update whose send time is known
+	responseSum time.Duration
+	responseMax time.Duration
+}
+
+// Count an input arriving before tick is stepped, false if it must be
+// discarded: older than the input in use, or for a tick after tick, which
+// no client can have seen the update for. response is the time since the
+// update it answers went out, negative if unknown.
+func (in *carInputs) take(sequence uint64, target, tick int32, response time.Duration) bool {
+	in.received++
+	if target > tick {
+		in.future++
+		return false
+	}
+
+	if (sequence != 0 && sequence <= in.sequence) || (target != 0 && target < in.target) {
+		in.stale++
+		return false
+	}
+
+	// Unsequenced inputs leave the sequence where it is, so an older input
+	// cannot slip in after them
+	if sequence != 0 {
+		in.sequence = sequence
+	}
+	in.target = target
+
+	if target != 0 {
+		late := max(tick-target, 0)
+		in.tagged++
+		in.ticksLate += late
+		in.maxLate = max(in.maxLate, late)
+		if late > 0 {
+			in.late++
+		}
+	}
+	if response >= 0 {
+		in.responses++
+		in.responseSum += response
+		in.responseMax = max(in.responseMax, response)
+	}
+	return true
+}
+
+// Statistics for race control
+func (in *carInputs) stats() *pb.InputStats {
+	stats := &pb.InputStats{
+		Sequence:     in.sequence,
+		Received:     in.received,
+		Stale:        in.stale,
+		Future:       in.future,
+		Late:         in.late,
+		MaxTicksLate: in.maxLate,
+		MaxResponse:  float32(in.responseMax.Seconds() * 1000),
+	}
+	if in.tagged > 0 {
+		stats.MeanTicksLate = float32(in.ticksLate) / float32(in.tagged)
+	}
+	if in.responses > 0 {
+		stats.MeanResponse = float32((in.responseSum / time.Duration(in.responses)).Seconds() * 1000)
+	}
+	return stats
+}
+
+// Input bookkeeping for a car, created on first use, caller holds s.mu
+func (s *RaceSession) carInputs(carId string) *carInputs {
+	in, ok := s.inputs[carId]
+	if !ok {
+		in = &carInputs{}
+		s.inputs[carId] = in
+	}
+	return in
+}
+
+// Let a car count its inputs from the start again, after it checks in or
+// opens a Drive stream, caller holds s.mu
+func (s *RaceSession) resetSequence(carId string) {
+	in := s.carInputs(carId)
+	in.sequence, in.target = 0, 0
+}
+
+// Start every car's input bookkeeping over for a new race, whose ticks count
+// from 0 again, caller holds s.mu. Inputs still in flight from the last race
+// target ticks the new one has not reached and are discarded.
+func (s *RaceSession) resetInputs() {
+	s.inputs = make(map[string]*carInputs)
+	s.updateSent = [inputHistory]sentUpdate{}
+}
+
+// Note when the update for a tick first went out, caller holds s.mu
+func (s *RaceSession) markSent(tick int32) {
+	sent := &s.updateSent[tick%inputHistory]
+	if sent.tick != tick || sent.at.IsZero() {
+		*sent = sentUpdate{tick: tick, at: time.Now()}
+	}
+}
+
+// Time since the update for a tick went out, negative if it is no longer known
+func (s *RaceSession) sinceSent(tick int32) time.Duration {
+	if tick < 0 {
+		return -1
+	}
+	sent := s.updateSent[tick%inputHistory]
+	if sent.tick != tick || sent.at.IsZero() {
+		return -1
+	}
+	return time.Since(sent.at)
+}
+
+// Make an input the car's latest, for the next tick, unless it is stale,
+// caller holds s.mu
+func (s *RaceSession) setInput(carId string, input *pb.PlayerInput) bool {
+	next := s.state.gameTick + 1
+	response := time.Duration(-1)
+	if input.GetTargetTick() != 0 {
+		response = s.sinceSent(input.GetTargetTick() - 1)
+	}
+	if !s.carInputs(carId).take(input.GetSequence(), input.GetTargetTick(), next, response) {
+		return false
+	}
+
+	s.playerInput[carId] = &PlayerInput{
+		steering:  input.GetSteering(),
+		throttle:  input.GetThrottle(),
+		brake:     input.GetBrake(),
+		pitRefuel: input.GetPitRefuel(),
+		pitTyres:  input.GetPitTyres(),
+	}
+	s.inputTick[carId] = next
+	s.lastActive = time.Now()
+	return true
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+
+	pb "server/proto"
+)
+
+func TestTakeInputs(t *testing.T) {
+	type input struct {
+		sequence uint64
+		target   int32
+		want     bool
+	}
+	tests := []struct {
+		name   string
+		tick   int32 // next tick to be stepped
+		inputs []input
+	}{
+		{"in order", 10, []input{{1, 10, true}, {2, 10, true}, {3, 10, true}}},
+		{"duplicate", 10, []input{{1, 10, true}, {1, 10, false}}},
+		{"reordered", 10, []input{{1, 9, true}, {3, 10, true}, {2, 9, false}}},
+		{"older target", 10, []input{{1, 10, true}, {2, 9, false}}},
+		{"late but newest", 10, []input{{1, 4, true}, {2, 6, true}}},
+		{"future target", 10, []input{{1, 11, false}, {2, 5000, false}, {3, 10, true}}},
+		{"untagged", 10, []input{{1, 10, true}, {2, 0, true}, {3, 1, true}}},
+		{"unsequenced keeps the mark", 10, []input{{50, 10, true}, {0, 0, true}, {1, 10, false}, {51, 10, true}}},
+		{"far behind", 10, []input{{500, 10, true}, {1, 10, false}, {450, 10, false}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := &carInputs{}
+			for i, input := range test.inputs {
+				if got := in.take(input.sequence, input.target, test.tick, -1); got != input.want {
+					t.Errorf("input %d (sequence %d, target %d): taken %v, want %v",
+						i, input.sequence, input.target, got, input.want)
+				}
+			}
+		})
+	}
+}
+
+func TestInputStats(t *testing.T) {
+	in := &carInputs{}
+	in.take(1, 8, 10, -1)  // 2 ticks late
+	in.take(2, 10, 10, -1) // on time
+	in.take(1, 10, 10, -1) // stale
+	in.take(3, 12, 10, -1) // future
+
+	stats := in.stats()
+	if stats.Received != 4 || stats.Stale != 1 || stats.Future != 1 || stats.Late != 1 {
+		t.Errorf("counts: %v", stats)
+	}
+	if stats.MaxTicksLate != 2 || stats.MeanTicksLate != 1 {
+		t.Errorf("lateness: %v", stats)
+	}
+	if stats.Sequence != 2 {
+		t.Errorf("sequence in use %d, want 2", stats.Sequence)
+	}
+}
+
+func TestInputsAfterRestart(t *testing.T) {
+	s, setup := testSession(t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	carId := s.config.carInfos[0].carId
+	send := func(sequence uint64, target int32, throttle float32) bool {
+		return s.setInput(carId, &pb.PlayerInput{Sequence: sequence, TargetTick: target, Throttle: throttle})
+	}
+
+	// Deep into the last race, so its inputs target ticks far ahead of the next
+	s.state.gameTick = 5000
+	if !send(900, 5001, 1) {
+		t.Fatal("input before the restart not taken")
+	}
+	if err := s.startSession(setup); err != nil {
+		t.Fatal(err)
+	}
+
+	if send(901, 5002, 1) {
+		t.Error("input from the last race taken after the restart")
+	}
+	if !send(1, s.state.gameTick+1, 0.5) {
+		t.Error("input counting from 1 after the restart discarded")
+	}
+	if got := s.playerInput[carId].throttle; got != 0.5 {
+		t.Errorf("throttle %v after the restart, want 0.5", got)
+	}
+}
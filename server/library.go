@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "server/proto"
+	"server/trackdata"
+)
+
+// A track file and the metadata at its top
+type trackMeta struct {
+	file string
+	trackdata.Meta
+}
+
+// Tracks in a directory sorted by id, in every format trackdata reads but
+// leaving out pit lane files. Files with bad metadata or a duplicate id are
+// skipped.
+func scanTracks(dir string) []trackMeta {
+	var paths []string
+	for _, ext := range trackdata.Extensions {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*"+ext))
+		paths = append(paths, matches...)
+	}
+
+	tracks := []trackMeta{}
+	files := make(map[string]string)
+	for _, path := range paths {
+		if strings.HasSuffix(path, pitLaneSuffix) {
+			continue
+		}
+
+		meta, err := trackdata.LoadMeta(path)
+		if err != nil {
+			log.Printf("Skipping track file: %v", err)
+			continue
+		}
+		if other, ok := files[meta.ID]; ok {
+			log.Printf("Skipping track file %s: id %q already used by %s", path, meta.ID, other)
+			continue
+		}
+		files[meta.ID] = path
+		tracks = append(tracks, trackMeta{file: path, Meta: meta})
+	}
+
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].ID < tracks[j].ID })
+	return tracks
+}
+
+// Path of a track in the tracks directory, by id or file name
+func trackPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid track name %q", name)
+	}
+
+	for _, meta := range scanTracks(tracksDir) {
+		file := filepath.Base(meta.file)
+		if meta.ID == name || file == name || strings.TrimSuffix(file, filepath.Ext(file)) == name {
+			return meta.file, nil
+		}
+	}
+	return "", fmt.Errorf("unknown track %q", name)
+}
+
+// Point a setup at a track by id or file name, or at a generated one when
+// the name is "generated" or there are generator settings
+func (setup *raceSetup) selectTrack(name string, generator *pb.TrackGenerator) error {
+	switch {
+	case generator != nil && name != "" && name != generatedTrack:
+		return fmt.Errorf("track %q and a generated track both given", name)
+	case generator != nil || name == generatedTrack:
+		// The name alone asks for a generated track with every default
+		variation, difficulty := float32(-1), float32(-1)
+		if generator != nil {
+			variation = optionalSetting(generator.WidthVariation)
+			difficulty = optionalSetting(generator.Difficulty)
+		}
+		setup.Generator = &trackdata.Generator{
+			Seed:           generator.GetSeed(),
+			Length:         generator.GetLength(),
+			Corners:        int(generator.GetCorners()),
+			Width:          generator.GetWidth(),
+			WidthVariation: variation,
+			Difficulty:     difficulty,
+		}
+		return nil
+	}
+
+	path, err := trackPath(name)
+	if err != nil {
+		return err
+	}
+	setup.TrackFile = path
+	setup.Generator = nil
+	return nil
+}
+
+// Generator settings of a setup as asked for, nil for a track file
+func (setup raceSetup) trackGenerator() *pb.TrackGenerator {
+	if setup.Generator == nil {
+		return nil
+	}
+	g := *setup.Generator
+	generator := &pb.TrackGenerator{
+		Seed:    g.Seed,
+		Length:  g.Length,
+		Corners: int32(g.Corners),
+		Width:   g.Width,
+	}
+	if g.WidthVariation >= 0 {
+		generator.WidthVariation = &g.WidthVariation
+	}
+	if g.Difficulty >= 0 {
+		generator.Difficulty = &g.Difficulty
+	}
+	return generator
+}
+
+// A generator setting that may be left unset, negative asking for the default
+func optionalSetting(value *float32) float32 {
+	if value == nil {
+		return -1
+	}
+	return *value
+}
+
+// Short description of a setup's track for logs
+func trackName(setup raceSetup) string {
+	if setup.Generator != nil {
+		return fmt.Sprintf("generated track %d", setup.generator().Seed)
+	}
+	return setup.TrackFile
+}
+
+// Listing entry for a track, measured from its prepared centerline
+func (meta trackMeta) summary() (*pb.TrackSummary, error) {
+	data, err := trackdata.Load(meta.file, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := data.Prepare(); err != nil {
+		return nil, err
+	}
+
+	left, right := data.Boundaries()
+	_, pitErr := os.Stat(pitLanePath(meta.file))
+
+	return &pb.TrackSummary{
+		TrackId:    meta.ID,
+		Name:       meta.Name,
+		Country:    meta.Country,
+		File:       filepath.Base(meta.file),
+		Length:     data.Length(),
+		Direction:  data.Direction(),
+		StartLeft:  left[0],
+		StartRight: right[0],
+		PitLane:    pitErr == nil,
+	}, nil
+}
+
+// ListTracks RPC - the tracks a session can be created on or moved to
+func (c *CarServer) ListTracks(ctx context.Context, req *pb.Empty) (*pb.TrackList, error) {
+	list := &pb.TrackList{}
+	for _, meta := range scanTracks(tracksDir) {
+		summary, err := meta.summary()
+		if err != nil {
+			log.Printf("Skipping track %s: %v", meta.ID, err)
+			continue
+		}
+		list.Tracks = append(list.Tracks, summary)
+	}
+	return list, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	pb "server/proto"
+	"server/trackdata"
+)
+
+func TestSelectGeneratedTrack(t *testing.T) {
+	zero := float32(0)
+	tests := []struct {
+		name      string
+		track     string
+		generator *pb.TrackGenerator
+		want      trackdata.Generator
+	}{
+		{"by name", generatedTrack, nil, trackdata.Generator{WidthVariation: -1, Difficulty: -1}},
+		{"settings", "", &pb.TrackGenerator{Seed: 9, Corners: 8}, trackdata.Generator{Seed: 9, Corners: 8, WidthVariation: -1, Difficulty: -1}},
+		{"zero settings", generatedTrack, &pb.TrackGenerator{WidthVariation: &zero, Difficulty: &zero}, trackdata.Generator{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setup := raceSetup{Seed: 1, NumCars: 2}
+			if err := setup.selectTrack(test.track, test.generator); err != nil {
+				t.Fatal(err)
+			}
+			if setup.Generator == nil || *setup.Generator != test.want {
+				t.Fatalf("generator %+v, want %+v", setup.Generator, test.want)
+			}
+			if _, err := newRaceConfig(setup); err != nil {
+				t.Errorf("generating the track: %v", err)
+			}
+
+			// Unset settings stay unset on the way back out
+			generator := setup.trackGenerator()
+			if (generator.WidthVariation != nil) != (test.want.WidthVariation >= 0) ||
+				(generator.Difficulty != nil) != (test.want.Difficulty >= 0) {
+				t.Errorf("settings reported as %v", generator)
+			}
+		})
+	}
+}
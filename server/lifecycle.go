@@ -0,0 +1,217 @@
+package main
+
+import (
+	"log"
+	"math/rand/v2"
+
+	pb "server/proto"
+)
+
+// Number of ticks in a span of simulated time
+func ticks(seconds float32) int32 {
+	return int32(seconds / fixedDt)
+}
+
+func (st *RaceState) setPhase(phase pb.RacePhase) {
+	log.Printf("Race phase %v -> %v at tick %d", st.phase, phase, st.gameTick)
+	st.phase = phase
+	st.phaseTick = st.gameTick
+}
+
+// Legacy RaceStatus.status text for the current phase
+func (st *RaceState) statusText() string {
+	switch st.phase {
+	case pb.RacePhase_PHASE_RACING, pb.RacePhase_PHASE_CHEQUERED:
+		return "racing"
+	case pb.RacePhase_PHASE_COOLDOWN, pb.RacePhase_PHASE_RESULTS:
+		return "finished"
+	default:
+		return "waiting"
+	}
+}
+
+// Whether cars are moving on track
+func (st *RaceState) running() bool {
+	return st.phase == pb.RacePhase_PHASE_RACING || st.phase == pb.RacePhase_PHASE_CHEQUERED
+}
+
+// Pick up cars that have checked in. Before the start they wait on the grid,
+// once the race is running late cars join from their grid slot.
+func (st *RaceState) checkInCars(inputs map[string]PlayerInput) {
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		if state.checkedIn || !inputs[car.carId].checkedIn {
+			continue
+		}
+
+		state.checkedIn = true
+		if st.firstCheckIn == 0 {
+			st.firstCheckIn = st.gameTick
+		}
+
+		if st.running() {
+			st.joinRace(state)
+			log.Printf("Car %s joined late at tick %d", car.carId, st.gameTick)
+		} else {
+			state.Status = pb.CarStatus_WAITING
+		}
+	}
+}
+
+// Put a checked-in car into the running race
+func (st *RaceState) joinRace(state *CarStateExtended) {
+	state.lapStartTick = st.gameTick
+
+	// Hot-lap cars wait in the garage until the track is free
+	if st.config.setup.RaceType == pb.RaceType_HOTLAP {
+		state.Status = pb.CarStatus_WAITING
+	} else {
+		state.Status = pb.CarStatus_RACING
+	}
+}
+
+// Any throttle before the lights go out is a jump start
+func (st *RaceState) watchForJumpStarts(inputs map[string]PlayerInput) {
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		if state.checkedIn && !state.jumpStart && inputs[car.carId].throttle > 0 {
+			state.jumpStart = true
+			log.Printf("Car %s jumped the start at tick %d", car.carId, st.gameTick)
+		}
+	}
+}
+
+// Lights out: every checked-in car starts racing and jump starts are penalised
+func (st *RaceState) startRacing() {
+	st.setPhase(pb.RacePhase_PHASE_RACING)
+	st.raceStartTick = st.gameTick
+	st.lightsOn = 0
+
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		if !state.checkedIn {
+			state.Status = pb.CarStatus_NOTREADY
+			continue
+		}
+
+		st.joinRace(state)
+		if state.jumpStart {
+			st.issuePenalty(state, "jump start", jumpStartPenalty)
+		}
+	}
+}
+
+// Take a car out of the race with the flag
+func (st *RaceState) finishCar(state *CarStateExtended) {
+	state.Status = pb.CarStatus_FINISHED
+	state.finishTick = st.gameTick
+	st.checkRaceRules(state)
+}
+
+// Whether the chequered flag should come out
+func (st *RaceState) raceOver() bool {
+	setup := st.config.setup
+
+	switch setup.RaceType {
+	case pb.RaceType_RACEBYLAPS:
+		// The leader has completed the race distance
+		for _, state := range st.carStates {
+			if state.Status == pb.CarStatus_FINISHED {
+				return true
+			}
+		}
+		return false
+
+	case pb.RaceType_HOTLAP:
+		if st.raceTimeLeft <= 0 {
+			return true
+		}
+		// Every checked-in car has done its run
+		done := false
+		for _, state := range st.carStates {
+			if state.checkedIn {
+				if state.Status != pb.CarStatus_FINISHED {
+					return false
+				}
+				done = true
+			}
+		}
+		return done
+
+	default:
+		return st.raceTimeLeft <= 0
+	}
+}
+
+// Advance the lifecycle at the end of a tick
+func (st *RaceState) updatePhase() {
+	elapsed := st.gameTick - st.phaseTick
+
+	switch st.phase {
+	case pb.RacePhase_PHASE_WAITING:
+		checkedIn := 0
+		for _, state := range st.carStates {
+			if state.checkedIn {
+				checkedIn++
+			}
+		}
+		if checkedIn == 0 {
+			return
+		}
+		if checkedIn < len(st.carStates) && st.gameTick-st.firstCheckIn < ticks(waitingTime) {
+			return
+		}
+
+		// Timed sessions go green without a standing start
+		if isTimedSession(st.config.setup.RaceType) {
+			st.startRacing()
+		} else {
+			st.setPhase(pb.RacePhase_PHASE_GRID)
+		}
+
+	case pb.RacePhase_PHASE_GRID:
+		if elapsed >= ticks(gridTime) {
+			st.setPhase(pb.RacePhase_PHASE_START_LIGHTS)
+
+			// The lights go out after a random hold once all are lit
+			hold := rand.New(&st.rng).Float32() * maxLightsOutDelay
+			st.lightsOutTick = st.gameTick + ticks(numStartLights*lightsInterval+hold)
+		}
+
+	case pb.RacePhase_PHASE_START_LIGHTS:
+		st.lightsOn = min(numStartLights, elapsed/ticks(lightsInterval))
+		if st.gameTick >= st.lightsOutTick {
+			st.startRacing()
+		}
+
+	case pb.RacePhase_PHASE_RACING:
+		if st.raceOver() {
+			st.setPhase(pb.RacePhase_PHASE_CHEQUERED)
+		}
+
+	case pb.RacePhase_PHASE_CHEQUERED:
+		stillRunning := false
+		for _, state := range st.carStates {
+			if onTrack(state) {
+				stillRunning = true
+			}
+		}
+		if stillRunning && elapsed < ticks(chequeredTime) {
+			return
+		}
+
+		// Cars still out when time is up are classified where they are
+		for _, car := range st.config.carInfos {
+			state := st.carStates[car.carId]
+			if onTrack(state) {
+				st.finishCar(state)
+			}
+		}
+		st.setPhase(pb.RacePhase_PHASE_COOLDOWN)
+
+	case pb.RacePhase_PHASE_COOLDOWN:
+		if elapsed >= ticks(cooldownTime) {
+			st.setPhase(pb.RacePhase_PHASE_RESULTS)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "server/proto"
+)
+
+// Session by id, an empty id is the default session
+func (c *CarServer) session(sessionId string) (*RaceSession, error) {
+	if sessionId == "" {
+		sessionId = defaultSession
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.sessions[sessionId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "session %q not found", sessionId)
+	}
+	return s, nil
+}
+
+// Setup for a session config, anything left empty comes from base
+func setupFromConfig(base raceSetup, req *pb.SessionConfig) (raceSetup, error) {
+	setup := base
+	setup.RaceType = req.GetRaceType()
+	setup.Grid = req.GetGrid()
+	setup.Seed = req.GetSeed()
+
+	// Session lengths default as on the command line
+	setup.Laps = totalLaps
+	setup.RaceTime = raceTime
+	switch setup.RaceType {
+	case pb.RaceType_QUALY:
+		setup.RaceTime = qualifyingTime
+	case pb.RaceType_HOTLAP:
+		setup.Laps = hotlapLaps
+		setup.RaceTime = hotlapTime
+	}
+	if req.GetLaps() > 0 {
+		setup.Laps = req.GetLaps()
+	}
+	if req.GetTime() > 0 {
+		setup.RaceTime = req.GetTime()
+	}
+	if req.GetSectors() > 0 {
+		setup.Sectors = req.GetSectors()
+	}
+	if req.GetCheckpoints() > 0 {
+		setup.Checkpoints = req.GetCheckpoints()
+	}
+	if req.GetPitStopTime() > 0 {
+		setup.PitStopTime = req.GetPitStopTime()
+	}
+	setup.MandatoryStops = req.GetMandatoryPitStops()
+	if req.GetFuel() > 0 {
+		setup.Fuel = req.GetFuel()
+	}
+	if setup.Seed == 0 {
+		setup.Seed = uint64(time.Now().UnixNano())
+	}
+
+	if req.GetTrack() != "" || req.GetGeneratedTrack() != nil {
+		if err := setup.selectTrack(req.GetTrack(), req.GetGeneratedTrack()); err != nil {
+			return setup, err
+		}
+	}
+
+	if len(req.GetCarIds()) > 0 {
+		for i, carId := range req.GetCarIds() {
+			if carId == "" || carId == observersID || slices.Contains(req.GetCarIds()[:i], carId) {
+				return setup, fmt.Errorf("invalid car id %q", carId)
+			}
+		}
+		setup.CarIds = req.GetCarIds()
+		setup.NumCars = len(setup.CarIds)
+	}
+
+	return setup, nil
+}
+
+// Session config the current setup was built from, caller holds s.mu
+func (s *RaceSession) sessionConfig() *pb.SessionConfig {
+	setup := s.config.setup
+	return &pb.SessionConfig{
+		RaceType:          setup.RaceType,
+		Laps:              setup.Laps,
+		Time:              setup.RaceTime,
+		Track:             s.config.track.TrackId,
+		CarIds:            setup.carIds(),
+		Grid:              setup.Grid,
+		Seed:              setup.Seed,
+		SimMode:           s.mode,
+		Sectors:           setup.sectors(),
+		Checkpoints:       setup.checkpoints(),
+		PitStopTime:       setup.pitStopTime(),
+		MandatoryPitStops: setup.MandatoryStops,
+		Fuel:              setup.startFuel(),
+		GeneratedTrack:    setup.trackGenerator(),
+	}
+}
+
+// Lobby listing for the session
+func (s *RaceSession) info() *pb.SessionInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	freeCars := []string{}
+	for _, car := range s.config.carInfos {
+		if !s.checkedIn[car.carId] {
+			freeCars = append(freeCars, car.carId)
+		}
+	}
+
+	return &pb.SessionInfo{
+		SessionId: s.id,
+		Config:    s.sessionConfig(),
+		Phase:     s.state.phase,
+		FreeCars:  freeCars,
+		GameTick:  s.state.gameTick,
+	}
+}
+
+// ListSessions RPC - every session, in id order
+func (c *CarServer) ListSessions(ctx context.Context, req *pb.Empty) (*pb.SessionList, error) {
+	c.mu.RLock()
+	sessions := make([]*RaceSession, 0, len(c.sessions))
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].id < sessions[j].id })
+
+	list := &pb.SessionList{}
+	for _, s := range sessions {
+		list.Sessions = append(list.Sessions, s.info())
+	}
+	return list, nil
+}
+
+// CreateSession RPC - opens a new lobby on the default session's setup,
+// overridden by the request
+func (c *CarServer) CreateSession(ctx context.Context, req *pb.SessionConfig) (*pb.SessionInfo, error) {
+	if err := checkAdminToken(ctx, c.adminToken); err != nil {
+		return nil, err
+	}
+
+	base, err := c.session(defaultSession)
+	if err != nil {
+		return nil, err
+	}
+	base.mu.RLock()
+	baseSetup := base.config.setup
+	base.mu.RUnlock()
+
+	setup, err := setupFromConfig(baseSetup, req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Hold a place under the cap while the track is built, outside the lock
+	c.mu.Lock()
+	if len(c.sessions)+c.reserved >= maxSessions {
+		c.mu.Unlock()
+		return nil, status.Errorf(codes.ResourceExhausted, "at most %d sessions", maxSessions)
+	}
+	c.reserved++
+	c.nextSession++
+	sessionId := fmt.Sprintf("lobby-%d", c.nextSession)
+	c.mu.Unlock()
+
+	recordDir := ""
+	if c.recordDir != "" {
+		recordDir = filepath.Join(c.recordDir, sessionId)
+	}
+
+	s, err := newRaceSession(sessionId, setup, req.GetSimMode(), recordDir)
+
+	c.mu.Lock()
+	c.reserved--
+	if err == nil {
+		c.sessions[sessionId] = s
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Printf("Session %s created: %v on %s (%v)", sessionId, setup.RaceType, trackName(setup), req.GetSimMode())
+	return s.info(), nil
+}
+
+// Remove a lobby and stop its tick loop and streams
+func (c *CarServer) closeSession(sessionId string) error {
+	if sessionId == defaultSession {
+		return status.Error(codes.FailedPrecondition, "the default session cannot be closed")
+	}
+
+	c.mu.Lock()
+	s, ok := c.sessions[sessionId]
+	delete(c.sessions, sessionId)
+	c.mu.Unlock()
+
+	if !ok {
+		return status.Errorf(codes.NotFound, "session %q not found", sessionId)
+	}
+	close(s.done)
+	log.Printf("Session %s closed", sessionId)
+	return nil
+}
+
+// Close lobbies nobody has used for lobbyIdleTime, for as long as the server runs
+func (c *CarServer) expireLobbies() {
+	ticker := time.NewTicker(lobbyIdleTime / 10)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.RLock()
+		var idle []string
+		for id, s := range c.sessions {
+			if id != defaultSession && s.idleFor() >= lobbyIdleTime {
+				idle = append(idle, id)
+			}
+		}
+		c.mu.RUnlock()
+
+		for _, id := range idle {
+			if err := c.closeSession(id); err == nil {
+				log.Printf("Session %s expired after %v idle", id, lobbyIdleTime)
+			}
+		}
+	}
+}
+
+// How long the session has gone without streams, check-ins or inputs
+func (s *RaceSession) idleFor() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.clients) > 0 || len(s.drivers) > 0 {
+		return 0
+	}
+	return time.Since(s.lastActive)
+}
+
+// Whether the session has been closed
+func (s *RaceSession) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// JoinSession RPC - check in to the requested car, or the first free one
+func (c *CarServer) JoinSession(ctx context.Context, req *pb.RegisterPlayer) (*pb.CheckInResponse, error) {
+	s, err := c.session(req.GetSessionId())
+	if err != nil {
+		return &pb.CheckInResponse{
+			Accepted: false,
+			Message:  "Session not found",
+		}, nil
+	}
+
+	carId := req.GetCarId()
+	if carId == "" {
+		// Claim the car under the lock so two joins never share one
+		s.mu.Lock()
+		for _, car := range s.config.carInfos {
+			if !s.checkedIn[car.carId] {
+				carId = car.carId
+				s.checkedIn[carId] = true
+				break
+			}
+		}
+		s.mu.Unlock()
+
+		if carId == "" {
+			return &pb.CheckInResponse{
+				Accepted: false,
+				Message:  "Session is full",
+			}, nil
+		}
+	}
+
+	return s.checkIn(carId), nil
+}
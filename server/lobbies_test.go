@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	pb "server/proto"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func testCarServer(t *testing.T) *CarServer {
+	t.Helper()
+	setup := raceSetup{Seed: 1, RaceType: pb.RaceType_HOTLAP, NumCars: 2, Laps: 1, RaceTime: 60}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCarServer(setup, pb.SimulationMode_REALTIME, "", "secret")
+
+	// Stop the tick loops of the sessions still open when the test ends
+	t.Cleanup(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, s := range c.sessions {
+			if !s.closed() {
+				close(s.done)
+			}
+		}
+	})
+	return c
+}
+
+func adminContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, token))
+}
+
+func TestCreateSessionNeedsToken(t *testing.T) {
+	c := testCarServer(t)
+	if _, err := c.CreateSession(context.Background(), &pb.SessionConfig{}); err == nil {
+		t.Error("lobby created without the admin token")
+	}
+	if _, err := c.CreateSession(adminContext("wrong"), &pb.SessionConfig{}); err == nil {
+		t.Error("lobby created with the wrong admin token")
+	}
+}
+
+func TestCreateSessionCap(t *testing.T) {
+	c := testCarServer(t)
+
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for range 2 * maxSessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.CreateSession(adminContext("secret"), &pb.SessionConfig{}); err == nil {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(c.sessions); got != maxSessions {
+		t.Errorf("%d sessions, want %d", got, maxSessions)
+	}
+	if got := created.Load(); got != maxSessions-1 {
+		t.Errorf("%d lobbies created, want %d", got, maxSessions-1)
+	}
+}
+
+func TestCloseSession(t *testing.T) {
+	c := testCarServer(t)
+	info, err := c.CreateSession(adminContext("secret"), &pb.SessionConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.session(info.SessionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.closeSession(info.SessionId); err != nil {
+		t.Fatal(err)
+	}
+	if !s.closed() {
+		t.Error("closed lobby still running")
+	}
+	if _, err := c.session(info.SessionId); err == nil {
+		t.Error("closed lobby still listed")
+	}
+	if err := c.closeSession(defaultSession); err == nil {
+		t.Error("default session closed")
+	}
+}
+
+func TestNoAdminToken(t *testing.T) {
+	if err := checkAdminToken(adminContext(""), ""); err == nil {
+		t.Error("empty token accepted when none is set")
+	}
+	if err := checkAdminToken(adminContext("secret"), "secret"); err != nil {
+		t.Errorf("admin token refused: %v", err)
+	}
+}
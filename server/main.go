@@ -1,12 +1,16 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"math/rand/v2"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
 	pb "server/proto" // your generated proto package
+	"server/trackdata"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -16,87 +20,368 @@ const (
 	port             = ":50051"
 	updateRate       = time.Second / 60 // 60 FPS
 	numCars          = 5
+	defaultTrack     = "barcelona"
+	generatedTrack   = "generated" // track name asking for a generated track
 	totalLaps        = 3
 	raceTime         = 600 // 10 minutes for time-based races
+	qualifyingTime   = 300 // seconds of qualifying
+	hotlapTime       = 900 // seconds of hot-lap session
+	hotlapLaps       = 3   // timed laps per hot-lap run
+	numSectors       = 3   // timing sectors per lap
+	numCheckpoints   = 12  // lap counting gates per lap, the first is the start line
 	observersallowed = true
 	observersID      = "OBSERVER"
 	observerstoken   = "OBSERVERTOKEN"
-	maxSpeed         = float32(300.0)
-	acceleration     = float32(200.0)
-	brakeForce       = float32(400.0)
-	friction         = float32(50.0)
-	turnSpeed        = float32(180.0)
+	adminTokenHeader = "x-admin-token"
+	sessionHeader    = "x-session-id" // race control target session
+	defaultSession   = "default"
+	maxSessions      = 16
+	lobbyIdleTime    = 10 * time.Minute // a lobby with no streams or inputs for this long is closed
+	tracksDir        = "./tracks"
+	lockstepTimeout  = 2 * time.Second // longest a silent car can hold up a lockstep tick
+	inputHistory     = 64              // ticks of update send times kept to time input responses
+
+	// Race lifecycle
+	waitingTime       = float32(30.0) // seconds to wait for the rest of the field after the first check-in
+	gridTime          = float32(3.0)  // seconds on the grid before the start lights
+	numStartLights    = 5
+	lightsInterval    = float32(1.0)   // seconds between start lights
+	maxLightsOutDelay = float32(3.0)   // seconds, random hold after the last light
+	chequeredTime     = float32(120.0) // seconds for the field to finish after the flag
+	cooldownTime      = float32(10.0)  // seconds between the last car finishing and the results
+	jumpStartPenalty  = int32(5000)    // milliseconds
+	gridSpacing       = float32(8.0)   // metres between grid slots
+	gridStagger       = float32(2.5)   // metres either side of the centerline
+
+	// Vehicle dynamics
+	gravity           = float32(9.81)
+	powerScale        = float32(5000.0)  // watts per point of CarInfo power
+	minDriveSpeed     = float32(5.0)     // m/s, keeps the drive force finite from standstill
+	brakeForce        = float32(15000.0) // newtons at full brake
+	dragCoefficient   = float32(0.8)     // 0.5 * air density * drag coefficient * frontal area
+	rollingResistance = float32(0.015)   // fraction of the car's weight
+	tyreGrip          = float32(1.6)     // tyre friction coefficient
+	maxSteerAngle     = float32(20.0)    // degrees at full lock
+	wheelbase         = float32(3.0)     // metres
+
+	// Aerodynamics: cars leave a wake in a cone behind them
+	wakeAngle        = float32(12.0) // degrees either side of the leading car's direction
+	wakeMinSpeed     = float32(10.0) // m/s, slower cars leave no wake to speak of
+	slipstreamRange  = float32(50.0) // metres behind a car that its slipstream reaches
+	slipstreamDrag   = float32(0.35) // fraction of drag saved right behind a car
+	dirtyAirRange    = float32(25.0) // metres behind a car that its dirty air reaches
+	dirtyAirGripLoss = float32(0.15) // fraction of cornering grip lost right behind a car
+
+	// Surfaces beyond the track edges
+	kerbWidth       = float32(1.0)         // metres
+	runoffWidth     = float32(15.0)        // metres of grass or gravel, grass beyond
+	kerbCurvature   = float32(1.0 / 250.0) // 1/m, corners tighter than this have kerbs
+	gravelCurvature = float32(1.0 / 120.0) // 1/m, corners tighter than this have gravel on the outside
+	curvatureSpan   = 3                    // points either side used to measure curvature
+	kerbFriction    = float32(0.9)
+	kerbDrag        = float32(0.01)
+	grassFriction   = float32(0.55)
+	grassDrag       = float32(0.06)
+	gravelFriction  = float32(0.45)
+	gravelDrag      = float32(0.3)
+
+	// Tyres and fuel
+	tyreWearRate = float32(1.0 / 40000) // wear per metre at full tyre load, half that when cruising
+	tyreGripLoss = float32(0.3)         // fraction of grip lost on worn out tyres
+	fuelPerJoule = float32(7e-8)        // kg of fuel per joule of engine work
+	fuelCapacity = float32(110.0)       // kg
+	startFuel    = float32(60.0)        // kg
+	refuelRate   = float32(12.0)        // kg per second in the pit box
+
+	// Track geometry
+	geometryCellSize = float32(20.0) // metres, spatial grid cell
+	geometryWindow   = int32(4)      // segments either side of a car's last one to search first
+
+	// Lap counting
+	checkpointSlack     = float32(3.0) // track progress allowed per metre driven before a move counts as a jump
+	checkpointTolerance = float32(5.0) // metres of progress a car may always make in a tick, for contact pushes
+	wrongWayTime        = float32(1.0) // seconds facing backwards before a car counts as going the wrong way
+	wrongWayMinSpeed    = float32(2.0) // m/s, slower cars are manoeuvring, not driving the wrong way
+
+	// Pit lane
+	pitLaneSuffix     = ".pit.csv"    // pit lane file next to a track file, in place of its extension
+	pitSpeedLimit     = float32(22.2) // m/s, 80 km/h
+	pitLimiterMargin  = float32(60.0) // metres of lane at either end before the speed limit
+	pitStopSpeed      = float32(0.5)  // m/s, slower than this in the pit lane is a stop
+	pitStopTime       = float32(3.0)  // seconds of service in the pit box
+	mandatoryPitStops = 0             // stops each car must make in a race
+	driveThroughTime  = int32(20000)  // milliseconds added for a drive-through not served by the flag
+
+	// Track limits
+	trackLimitsMargin   = float32(1.0)  // metres past the edge before a car counts as off track
+	trackLimitsCutLimit = float32(15.0) // metres past the edge that count as cutting the track
+	trackLimitsWarnings = 3             // violations allowed before a penalty is issued
+	trackLimitsPenalty  = int32(5000)   // milliseconds, per penalty after the warnings
+	cutTrackPenalty     = int32(10000)  // milliseconds, issued immediately for cutting
+
+	// Collisions
+	carCollisionRadius      = float32(2.5)     // metres, cars are modelled as circles
+	collisionRestitution    = float32(0.3)     // 0 = cars stick together, 1 = fully elastic
+	collisionYawFactor      = float32(0.5)     // how much a sideways hit turns the car
+	collisionPenaltyImpulse = float32(15000.0) // kg*m/s, hits harder than this are investigated
+	collisionPenalty        = int32(5000)      // milliseconds, for the car at fault
 )
 
 type TrackPoint struct {
 	centerX    float32
 	centerY    float32
+	centerZ    float32 // elevation, metres
 	widthLeft  float32
 	widthRight float32
+	banking    float32 // degrees, positive raises the left edge
 }
 
 type PlayerInput struct {
 	steering  float32
 	throttle  float32
 	brake     float32
-	timestamp int32
+	pitRefuel float32 // kg, for the next service stop
+	pitTyres  bool
+	checkedIn bool // set by the server, not the player
 }
 
 type CarInfo struct {
-	carId  string
-	power  float32
-	weight float32
-	x      float32
-	y      float32
-	z      float32
+	carId   string
+	power   float32
+	weight  float32
+	x       float32
+	y       float32
+	z       float32
+	heading float32 // grid slot heading, degrees
 }
 
 // Extended car state for lap detection
 type CarStateExtended struct {
 	*pb.CarState
-	lastProgress    float32
-	crossedFinish   bool
-	bestLapTime     float32
-	currentLapStart time.Time
-	lapTimes        []float32
+	lastArc       float32 // arc length from the start line on the last tick
+	trackSegment  int32   // centerline segment on the last tick, -1 before the first
+	crossedFinish bool
+	bestLapTime   float32
+	lapStartTick  int32
+	lapValid      bool // false once the current lap breaks track limits
+	lapTimes      []float32
+	checkedIn     bool
+	jumpStart     bool
+	finishTick    int32 // tick the car took the flag, 0 until then
+	timeAdded     int32 // milliseconds added to the race time for a penalty not served by the flag
+
+	// Sector timing
+	sector          int32 // current sector, 0-based
+	sectorStartTick int32
+	sectorTimes     []float32 // current lap so far
+	lastSectorTimes []float32 // last complete lap
+	bestSectorTimes []float32 // personal bests, 0 where not set
+
+	// Timing line: the tick each track point was passed, one slice per lap,
+	// indexed by distance in track points since the start line
+	distance   int32 // where the car is now
+	covered    int32 // furthest distance reached, -1 until the car crosses the start line
+	lastPoint  int32
+	timingLine [][]int32
+
+	// Penalties applied, including rescinded time already served
+	penaltyCount int32
+	penaltyTime  int32 // milliseconds
+
+	// Lap counting: gates passed in order since the grid, including the
+	// start line, so lap n is done at gate n*checkpoints+1
+	checkpoints       int32
+	missedCheckpoints int32
+	lapCut            bool  // skipped a gate on the current lap, which will not count
+	wrongWayTicks     int32 // consecutive ticks facing backwards
+
+	// Pit lane
+	pitSegment   int32   // pit lane segment on the last tick, -1 before the first
+	pitEntered   bool    // came into the pit lane through the entry
+	pitStopped   bool    // has stopped on this visit, one stop per visit
+	pitHold      int32   // ticks of service left in the pit box
+	pitRefuel    float32 // kg going in during the service
+	pitTyres     bool    // new tyres going on during the service
+	stopGo       bool    // stopped in the pit box serving a stop-go penalty
+	disqualified string
+
+	// Track limits
+	offTrack             bool
+	cutTrack             bool
+	trackLimitViolations int32
 }
 
-type CarServer struct {
-	pb.UnimplementedCarServiceServer
-	mu           sync.RWMutex
-	carInfos     []CarInfo
-	carStates    map[string]*CarStateExtended
-	playerInput  map[string]*PlayerInput
-	authTokens   map[string]string
-	penalties    map[string]*pb.CarPenalty
-	raceStatus   *pb.RaceStatus
-	raceStarted  time.Time
+// Parameters that, together with the inputs, fully determine a race
+type raceSetup struct {
+	TrackFile      string               `json:"track_file"`
+	Generator      *trackdata.Generator `json:"generator,omitempty"` // generated track in place of TrackFile, seed 0 follows Seed
+	RaceType       pb.RaceType          `json:"race_type"`
+	Laps           int32                `json:"laps"`
+	RaceTime       int32                `json:"race_time"` // seconds, for time-based races
+	NumCars        int                  `json:"num_cars"`
+	CarIds         []string             `json:"car_ids,omitempty"` // default A, B, C... up to NumCars
+	Grid           []string             `json:"grid,omitempty"`    // starting order, car ids; default A, B, C...
+	Seed           uint64               `json:"seed"`
+	Sectors        int32                `json:"sectors,omitempty"`             // timing sectors, default numSectors
+	Checkpoints    int32                `json:"checkpoints,omitempty"`         // lap counting gates, default numCheckpoints
+	PitStopTime    float32              `json:"pit_stop_time,omitempty"`       // seconds of service, default pitStopTime
+	MandatoryStops int32                `json:"mandatory_pit_stops,omitempty"` // races only
+	Fuel           float32              `json:"fuel,omitempty"`                // kg at the start, default startFuel
+}
+
+// Race control action, applied at the start of a tick
+type raceCommand struct {
+	Tick        int32          `json:"tick"`
+	Kind        string         `json:"kind"` // "start", "abort", "penalty" or "rescind"
+	CarId       string         `json:"car_id,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	Duration    int32          `json:"duration,omitempty"` // milliseconds, for penalties
+	PenaltyKind pb.PenaltyKind `json:"penalty_kind,omitempty"`
+}
+
+// Static race data, never changed by the simulation
+type RaceConfig struct {
+	setup      raceSetup
+	track      *pb.TrackInfo
+	centerline []TrackPoint
+	geometry   *trackGeometry
+	pitLane    *trackGeometry // nil when the track has no pit lane
+	surfaces   *surfaceMap
+	carInfos   []CarInfo
+}
+
+// Simulation state after one tick
+type RaceState struct {
+	config       *RaceConfig
 	gameTick     int32
-	clients      map[chan *pb.RaceUpdate]struct{}
-	track        *pb.TrackInfo
-	raceType     pb.RaceType
-	raceLaps     int32
 	raceTimeLeft int32 // seconds remaining for time-based races
+
+	// Lifecycle
+	phase         pb.RacePhase
+	phaseTick     int32 // tick the current phase started
+	firstCheckIn  int32 // tick the first car checked in, 0 before that
+	lightsOn      int32
+	lightsOutTick int32
+	raceStartTick int32
+	aborted       bool // ended by race control, no follow-up session
+	carStates     map[string]*CarStateExtended
+	penalties     map[string]*pb.CarPenalty
+	contacts      []*pb.ContactEvent // contacts during this tick
+	rng           rand.PCG           // seeded from the setup, for stochastic effects
+}
+
+// Lobby server: routes CarService calls to the session they name
+type CarServer struct {
+	pb.UnimplementedCarServiceServer
+	mu          sync.RWMutex
+	sessions    map[string]*RaceSession
+	nextSession int
+	reserved    int // lobbies being built, counted against maxSessions
+	recordDir   string
+	adminToken  string // needed to create lobbies
+}
+
+// One race with its own track, cars, tick loop and subscribers
+type RaceSession struct {
+	id          string
+	mu          sync.RWMutex
+	config      *RaceConfig
+	state       *RaceState
+	playerInput map[string]*PlayerInput
+	authTokens  map[string]string
+	clients     map[chan *pb.RaceUpdate]struct{}
+	drivers     map[chan *pb.DriveUpdate]string // Drive streams, to the car each drives
+	recorder    *replayRecorder                 // nil unless replays are recorded
+	commands    []raceCommand                   // race control actions for the next tick
+	paused      bool
+
+	// Simulation driver
+	mode         pb.SimulationMode
+	checkedIn    map[string]bool       // cars that have checked in
+	inputTick    map[string]int32      // tick each car's latest input is for
+	inputs       map[string]*carInputs // ordering and timing of each car's inputs
+	updateSent   [inputHistory]sentUpdate
+	inputArrived chan struct{} // wakes a waiting headless driver
+	done         chan struct{} // closed with the session, stops the driver
+	lastActive   time.Time     // last check-in, input or stream, for lobby expiry
+	racesRun     int
 }
 
+var (
+	seed       = flag.Uint64("seed", 0, "simulation seed, 0 picks one from the clock")
+	recordDir  = flag.String("record", "", "directory to save race replays to")
+	replayFile = flag.String("replay", "", "re-simulate a saved replay, verify it and exit")
+	simMode    = flag.String("mode", "realtime", "simulation mode: realtime, fast or lockstep")
+	sessionArg = flag.String("race", "racebylaps", "session type: hotlap, qualy, racebylaps or racebytime")
+	adminToken = flag.String("admin-token", "", "token for the race control service and lobby creation, empty disables both")
+	trackArg   = flag.String("track", defaultTrack, "track id or file name in the tracks directory, or generated")
+)
+
 func main() {
+	flag.Parse()
+
+	if *replayFile != "" {
+		if err := runReplay(*replayFile); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		return
+	}
+
+	raceType, ok := pb.RaceType_value[strings.ToUpper(*sessionArg)]
+	if !ok {
+		log.Fatalf("Unknown session type %q", *sessionArg)
+	}
+
+	setup := raceSetup{
+		RaceType:       pb.RaceType(raceType),
+		Laps:           totalLaps,
+		RaceTime:       raceTime,
+		NumCars:        numCars,
+		Seed:           *seed,
+		MandatoryStops: mandatoryPitStops,
+	}
+	switch setup.RaceType {
+	case pb.RaceType_QUALY:
+		setup.RaceTime = qualifyingTime
+	case pb.RaceType_HOTLAP:
+		setup.Laps = hotlapLaps
+		setup.RaceTime = hotlapTime
+	}
+	if setup.Seed == 0 {
+		setup.Seed = uint64(time.Now().UnixNano())
+	}
+	if err := setup.selectTrack(*trackArg, nil); err != nil {
+		log.Fatalf("Unknown track: %v", err)
+	}
+
+	mode, ok := pb.SimulationMode_value[strings.ToUpper(*simMode)]
+	if !ok {
+		log.Fatalf("Unknown simulation mode %q", *simMode)
+	}
+
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
 	grpcServer := grpc.NewServer()
-	carServer := NewCarServer()
+	carServer := NewCarServer(setup, pb.SimulationMode(mode), *recordDir, *adminToken)
 
 	pb.RegisterCarServiceServer(grpcServer, carServer)
+	if *adminToken != "" {
+		pb.RegisterRaceControlServiceServer(grpcServer, NewRaceControlServer(carServer, *adminToken))
+	} else {
+		log.Printf("No -admin-token given, race control and lobby creation are disabled")
+	}
 	reflection.Register(grpcServer)
 
 	log.Printf("🏎️  Racing server listening on %s", port)
-	log.Printf("Race type: %v", carServer.raceType)
-	if carServer.raceType == pb.RaceType_RACEBYLAPS {
-		log.Printf("Total laps: %d", carServer.raceLaps)
-	} else if carServer.raceType == pb.RaceType_RACEBYTIME {
-		log.Printf("Race duration: %d seconds", raceTime)
+	log.Printf("Simulation mode: %v", pb.SimulationMode(mode))
+	log.Printf("Race type: %v (seed %d)", setup.RaceType, setup.Seed)
+	if setup.RaceType == pb.RaceType_RACEBYLAPS {
+		log.Printf("Total laps: %d", setup.Laps)
+	} else {
+		log.Printf("Session duration: %d seconds", setup.RaceTime)
 	}
 
 	if err := grpcServer.Serve(lis); err != nil {
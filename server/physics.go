@@ -8,112 +8,197 @@ import (
 	pb "server/proto"
 )
 
-// Physics/game loop
-func (s *CarServer) physicsLoop() {
+// Real-time driver: steps the simulation once per updateRate
+func (s *RaceSession) physicsLoop() {
 	ticker := time.NewTicker(updateRate)
 	defer ticker.Stop()
-	last := time.Now()
 
-	for range ticker.C {
-		now := time.Now()
-		dt := float32(now.Sub(last).Seconds())
-		last = now
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
 
 		s.mu.Lock()
-		s.gameTick++
-
-		// Update race time for time-based races
-		if s.raceType == pb.RaceType_RACEBYTIME {
-			s.raceTimeLeft = int32(raceTime - int(now.Sub(s.raceStarted).Seconds()))
-			if s.raceTimeLeft <= 0 {
-				s.raceTimeLeft = 0
-				s.raceStatus.Status = "finished"
-			}
-		}
+		s.tick()
+		s.mu.Unlock()
+	}
+}
 
-		var maxLap int32 = 0
-		var maxProgress float32 = 0
-
-		for _, car := range s.carInfos {
-			state := s.carStates[car.carId]
-			input := s.playerInput[car.carId]
-
-			// Update penalty timers
-			if penalty, hasPenalty := s.penalties[car.carId]; hasPenalty {
-				penalty.RemainingPenalty -= int32(dt * 1000) // Convert to milliseconds
-				if penalty.RemainingPenalty <= 0 {
-					delete(s.penalties, car.carId)
-					state.Status = pb.CarStatus_RACING
-				} else {
-					state.Status = pb.CarStatus_SERVINGPENALTY
-				}
-			}
+// Step the race with the latest inputs and publish the result, caller holds s.mu.
+// A paused race only republishes its state.
+func (s *RaceSession) tick() {
+	if s.paused {
+		s.broadcast(s.state.createRaceUpdate())
+		return
+	}
 
-			// Only update physics if car is racing (not serving penalty or finished)
-			if state.Status == pb.CarStatus_RACING {
-				s.updateCarPhysics(state, input, dt, now)
-
-				// Determine leader (by lap and progress along track)
-				progress := s.calculateTrackProgress(state.Position)
-				if state.Lap > maxLap || (state.Lap == maxLap && progress > maxProgress) {
-					maxLap = state.Lap
-					maxProgress = progress
-				}
-
-				// Check if finished (for lap-based races)
-				if s.raceType == pb.RaceType_RACEBYLAPS && state.Lap >= s.raceLaps {
-					state.Status = pb.CarStatus_FINISHED
-				}
-
-				// For time-based races, finish when time runs out
-				if s.raceType == pb.RaceType_RACEBYTIME && s.raceTimeLeft <= 0 {
-					state.Status = pb.CarStatus_FINISHED
-				}
+	inputs := make(map[string]PlayerInput, len(s.playerInput))
+	for carId, input := range s.playerInput {
+		tickInput := *input
+		tickInput.checkedIn = s.checkedIn[carId]
+		inputs[carId] = tickInput
+	}
+
+	commands := s.commands
+	s.commands = nil
+
+	prevPhase := s.state.phase
+	s.state = stepRace(s.state, inputs, commands)
+	finished := prevPhase != pb.RacePhase_PHASE_RESULTS && s.state.phase == pb.RacePhase_PHASE_RESULTS
+
+	if s.recorder != nil {
+		s.recorder.record(s.state.gameTick, inputs, commands)
+	}
+
+	if finished {
+		log.Printf("🏁 Race finished at tick %d, state hash %016x", s.state.gameTick, s.state.hash())
+		if s.recorder != nil {
+			if path, err := s.recorder.save(s.state); err != nil {
+				log.Printf("Failed to save replay: %v", err)
+			} else {
+				log.Printf("Replay saved to %s", path)
 			}
 		}
+	}
 
-		s.raceStatus.GameTick = s.gameTick
+	s.broadcast(s.state.createRaceUpdate())
 
-		// Check if race is finished
-		if s.raceType == pb.RaceType_RACEBYLAPS && maxLap >= s.raceLaps {
-			s.raceStatus.Status = "finished"
+	// Qualifying is followed by the race, headless servers go straight on to
+	// the next race. Aborted sessions wait for race control.
+	if finished && !s.state.aborted {
+		if s.config.setup.RaceType == pb.RaceType_QUALY {
+			s.startRaceFromQualifying()
+		} else if s.mode != pb.SimulationMode_REALTIME {
+			s.restartRace()
 		}
+	}
+}
 
-		// Create update
-		update := s.createRaceUpdate()
-		for clientChan := range s.clients {
-			select {
-			case clientChan <- update:
-			default:
-			}
+// Send an update to every streaming client, and an ack to every Drive
+// stream, without blocking, caller holds s.mu
+func (s *RaceSession) broadcast(update *pb.RaceUpdate) {
+	update.RaceStatus.Paused = s.paused
+	s.markSent(update.GameTick)
+	for clientChan := range s.clients {
+		select {
+		case clientChan <- update:
+		default:
 		}
+	}
 
-		s.mu.Unlock()
+	if len(s.drivers) == 0 {
+		return
+	}
+	cars := make(map[string]*pb.CarState, len(update.Cars))
+	for _, car := range update.Cars {
+		cars[car.CarId] = car
+	}
+	for driverChan, carId := range s.drivers {
+		ack := &pb.DriveUpdate{
+			GameTick: update.GameTick,
+			Sequence: s.carInputs(carId).sequence,
+			Car:      cars[carId],
+			Phase:    update.RaceStatus.Phase,
+			Paused:   s.paused,
+		}
+		select {
+		case driverChan <- ack:
+		default:
+		}
 	}
 }
 
 // Physics for each car
-func (s *CarServer) updateCarPhysics(state *CarStateExtended, input *PlayerInput, dt float32, now time.Time) {
-	// Apply acceleration/brake
-	if input.throttle > 0 {
-		state.Speed += acceleration * input.throttle * dt
-	} else if input.brake > 0 {
-		state.Speed -= brakeForce * input.brake * dt
-	} else {
-		state.Speed -= friction * dt
+func (st *RaceState) updateCarPhysics(car CarInfo, state *CarStateExtended, input PlayerInput) {
+	// A car stopped in its pit box stays there until released
+	if st.holdInPitBox(state) {
+		return
+	}
+
+	dt := fixedDt
+	mass := state.mass(car)
+	speed := state.Speed
+	geometry := st.config.geometry
+	rad := float64(state.Heading) * math.Pi / 180
+
+	// The surface the car ended the last tick on sets its grip and drag
+	friction, surfaceDrag := st.config.surfaces.handling(state.Surface)
+	grip := state.grip() * friction
+
+	// Grip bounds every tyre force: traction, braking and cornering
+	maxTyreForce := grip * mass * gravity
+
+	// Engine: constant power, so the drive force drops as speed rises. An
+	// empty tank gives no drive at all.
+	driveForce := float32(0)
+	if input.throttle > 0 && state.Fuel > 0 {
+		driveForce = car.power * powerScale * input.throttle / max(speed, minDriveSpeed)
+		driveForce = min(driveForce, maxTyreForce)
+	}
+
+	// Brakes: a fixed force, so heavier cars need longer to stop
+	brakingForce := float32(0)
+	if input.throttle <= 0 && input.brake > 0 {
+		brakingForce = min(brakeForce*input.brake, maxTyreForce)
+	}
+
+	// Resistance: aerodynamic drag, less in another car's slipstream, and
+	// rolling resistance, more off the asphalt
+	dragForce := dragCoefficient * speed * speed * (1 - state.Slipstream)
+	rollingForce := float32(0)
+	if speed > 0 {
+		rollingForce = (rollingResistance + surfaceDrag) * mass * gravity
+	}
+
+	// Slope and banking where the car was on the last tick
+	slopeForce := float32(0)
+	bankRise := float32(0)
+	if state.trackSegment >= 0 {
+		// Gravity pulls back uphill and forward downhill
+		dx, dy := geometry.direction(state.trackSegment)
+		along := float32(math.Cos(rad))*dx + float32(math.Sin(rad))*dy
+		grade := geometry.gradient(state.trackSegment)
+		slopeForce = mass * gravity * grade / float32(math.Sqrt(float64(1+grade*grade))) * along
+
+		bankRise = float32(math.Tan(float64(geometry.bankingAt(state.trackSegment)) * math.Pi / 180))
 	}
 
+	state.Speed += (driveForce - brakingForce - dragForce - rollingForce - slopeForce) / mass * dt
 	if state.Speed < 0 {
 		state.Speed = 0
 	}
-	if state.Speed > maxSpeed {
-		state.Speed = maxSpeed
+
+	// Pit lane speed limiter
+	if state.Status == pb.CarStatus_INPITS {
+		state.Speed = min(state.Speed, pitSpeedLimit)
 	}
 
-	// Steering
-	if state.Speed > 10 && input.steering != 0 {
-		turnRate := turnSpeed * (state.Speed / maxSpeed)
-		state.Heading += turnRate * input.steering * dt
+	// Steering: kinematic yaw rate, capped by the lateral grip limit, which
+	// drops in dirty air. Banking raises the limit for turns away from the
+	// raised edge and lowers it for the others.
+	cornerForce := float32(0)
+	if state.Speed > 0 && input.steering != 0 {
+		steerAngle := float64(maxSteerAngle*input.steering) * math.Pi / 180
+		yawRate := state.Speed * float32(math.Tan(steerAngle)) / wheelbase
+
+		cornerGrip := grip * (1 - state.DirtyAir)
+		bank := bankRise
+		if yawRate > 0 {
+			// Turning left, towards the raised edge
+			bank = -bank
+		}
+		lateralLimit := max(0, gravity*(cornerGrip+bank)/max(1-cornerGrip*bank, 0.1))
+		gripYawRate := lateralLimit / state.Speed
+		if yawRate > gripYawRate {
+			yawRate = gripYawRate
+		} else if yawRate < -gripYawRate {
+			yawRate = -gripYawRate
+		}
+		cornerForce = mass * state.Speed * float32(math.Abs(float64(yawRate)))
+
+		state.Heading += yawRate * 180 / math.Pi * dt
 		for state.Heading < 0 {
 			state.Heading += 360
 		}
@@ -122,33 +207,93 @@ func (s *CarServer) updateCarPhysics(state *CarStateExtended, input *PlayerInput
 		}
 	}
 
-	rad := float64(state.Heading) * math.Pi / 180
+	rad = float64(state.Heading) * math.Pi / 180
 	dx := float32(math.Cos(rad)) * state.Speed * dt
 	dy := float32(math.Sin(rad)) * state.Speed * dt
 
 	state.Position.X += dx
 	state.Position.Y += dy
 
-	// Lap detection
-	currentProgress := s.calculateTrackProgress(state.Position)
+	// Tyres wear with the force they carry, the tank empties with the work done
+	tyreForce := float32(math.Hypot(float64(max(driveForce, brakingForce)), float64(cornerForce)))
+	wearTyres(state, tyreForce/maxTyreForce, state.Speed*dt)
+	burnFuel(car, state, input.throttle)
+
+	// One track lookup per car per tick, starting from where it was last
+	loc := geometry.locate(state.Position, state.trackSegment)
+	located := state.trackSegment >= 0
+	state.trackSegment = loc.segment
+	state.Position.Z = geometry.heightAt(loc)
 
-	// Detect crossing finish line (progress wraps from ~1.0 to ~0.0)
-	if state.lastProgress > 0.9 && currentProgress < 0.1 && state.Speed > 0 {
-		state.Lap++
+	// The pit lane counts as track
+	excess := geometry.edgeExcess(loc)
+	if st.config.pitLane != nil {
+		excess = min(excess, st.updatePitLane(state, input))
+	}
+	st.checkTrackLimits(state, excess)
+	state.Surface = st.config.surfaces.surfaceAt(loc, excess)
+	st.checkWrongWay(state, loc.segment)
+
+	// Lap counting through the checkpoint gates, from the second lookup on
+	if located {
+		st.updateCheckpoints(state, loc.arc, max(speed, state.Speed))
+	}
+	state.lastArc = loc.arc
+
+	if state.crossedFinish {
+		st.updateSector(state, loc.arc/geometry.length)
+		st.updateTimingLine(state, loc.segment)
+	}
+}
 
+// Count a lap for a car that just crossed the finish line
+func (st *RaceState) completeLap(state *CarStateExtended) {
+	setup := st.config.setup
+
+	// The line ends the last sector
+	if state.sector == setup.sectors()-1 {
+		st.completeSector(state)
+	}
+	if len(state.sectorTimes) == int(setup.sectors()) {
+		state.lastSectorTimes = state.sectorTimes
+	}
+	state.sectorTimes = nil
+	state.sector = 0
+	state.sectorStartTick = st.gameTick
+
+	state.Lap++
+
+	lapTime := float32(st.gameTick-state.lapStartTick) * fixedDt
+	valid := state.lapValid
+	state.lapStartTick = st.gameTick
+	state.lapValid = true
+
+	// The first lap of a timed session is the out-lap
+	if isTimedSession(setup.RaceType) && state.Lap == 1 {
+		log.Printf("Car %s completed out-lap", state.CarId)
+	} else {
 		// Record lap time
-		lapTime := float32(now.Sub(state.currentLapStart).Seconds())
 		state.lapTimes = append(state.lapTimes, lapTime)
 
-		// Update best lap
-		if state.bestLapTime == 0 || lapTime < state.bestLapTime {
+		// Update best lap, invalidated laps do not count
+		if valid && (state.bestLapTime == 0 || lapTime < state.bestLapTime) {
 			state.bestLapTime = lapTime
 		}
 
-		state.currentLapStart = now
-		log.Printf("Car %s completed lap %d in %.2fs (best: %.2fs)",
-			state.CarId, state.Lap, lapTime, state.bestLapTime)
+		validity := ""
+		if !valid {
+			validity = " (invalidated)"
+		}
+		log.Printf("Car %s completed lap %d in %.2fs%s (best: %.2fs)",
+			state.CarId, state.Lap, lapTime, validity, state.bestLapTime)
 	}
 
-	state.lastProgress = currentProgress
+	// Cars finish at the first line crossing after the flag, or once they
+	// have done the race distance or their hot-lap run
+	switch {
+	case st.phase == pb.RacePhase_PHASE_CHEQUERED,
+		setup.RaceType == pb.RaceType_RACEBYLAPS && state.Lap >= setup.Laps,
+		setup.RaceType == pb.RaceType_HOTLAP && state.Lap > setup.Laps:
+		st.finishCar(state)
+	}
 }
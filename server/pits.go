@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	pb "server/proto"
+)
+
+// Seconds of service in the pit box
+func (setup raceSetup) pitStopTime() float32 {
+	if setup.PitStopTime > 0 {
+		return setup.PitStopTime
+	}
+	return pitStopTime
+}
+
+// Penalties are served as a stop-go in the pit lane, or in place on tracks
+// without one
+func (st *RaceState) penaltyKind() pb.PenaltyKind {
+	if st.config.pitLane != nil {
+		return pb.PenaltyKind_PENALTY_STOP_GO
+	}
+	return pb.PenaltyKind_PENALTY_TIME
+}
+
+// Track where the car is relative to the pit lane and return how far it is
+// outside the lane, so the lane counts as track for track limits
+func (st *RaceState) updatePitLane(state *CarStateExtended, input PlayerInput) float32 {
+	pit := st.config.pitLane
+	loc := pit.locate(state.Position, state.pitSegment)
+	state.pitSegment = loc.segment
+	excess := pit.edgeExcess(loc)
+
+	// The speed limit covers the lane between the entry and exit roads
+	inLimiter := excess <= trackLimitsMargin &&
+		loc.arc >= pitLimiterMargin && loc.arc <= pit.length-pitLimiterMargin
+
+	switch {
+	case inLimiter && state.Status == pb.CarStatus_RACING:
+		state.Status = pb.CarStatus_INPITS
+		state.pitEntered = loc.arc < pit.length/2
+		state.pitStopped = false
+		log.Printf("Car %s enters the pit lane", state.CarId)
+
+	case !inLimiter && state.Status == pb.CarStatus_INPITS:
+		state.Status = pb.CarStatus_RACING
+		st.leavePitLane(state, state.pitEntered && loc.arc > pit.length/2)
+
+	case state.Status == pb.CarStatus_INPITS && !state.pitStopped && state.Speed < pitStopSpeed:
+		st.startPitStop(state, input)
+	}
+
+	return excess
+}
+
+// Coming to a halt in the pit lane is a stop in the pit box: a stop-go
+// penalty if the car has one to serve, otherwise a service stop with the
+// fuel and tyres the car's input asks for. Refuelling adds to the stop time.
+func (st *RaceState) startPitStop(state *CarStateExtended, input PlayerInput) {
+	state.pitStopped = true
+	state.Speed = 0
+
+	if penalty, hasPenalty := st.penalties[state.CarId]; hasPenalty && penalty.Kind == pb.PenaltyKind_PENALTY_STOP_GO {
+		state.stopGo = true
+		log.Printf("Car %s stops to serve its stop-go penalty", state.CarId)
+		return
+	}
+
+	state.pitTyres = input.pitTyres
+	state.pitRefuel = max(0, min(input.pitRefuel, fuelCapacity-state.Fuel))
+	state.pitHold = ticks(st.config.setup.pitStopTime() + state.pitRefuel/refuelRate)
+	log.Printf("Car %s stops in its pit box (%.1f kg fuel, new tyres: %v)", state.CarId, state.pitRefuel, state.pitTyres)
+}
+
+// Hold a car stopped in its pit box, returns false once it is free to go
+func (st *RaceState) holdInPitBox(state *CarStateExtended) bool {
+	switch {
+	case state.stopGo:
+		penalty := st.penalties[state.CarId]
+		penalty.RemainingPenalty -= tickMillis
+		state.PitStopLeft = float32(max(penalty.RemainingPenalty, 0)) / 1000
+		if penalty.RemainingPenalty <= 0 {
+			delete(st.penalties, state.CarId)
+			state.stopGo = false
+			log.Printf("Car %s served its stop-go penalty", state.CarId)
+		}
+
+	case state.pitHold > 0:
+		state.pitHold--
+		state.PitStopLeft = float32(state.pitHold) * fixedDt
+		if state.pitHold == 0 {
+			state.PitStops++
+			state.Fuel += state.pitRefuel
+			if state.pitTyres {
+				state.TyreWear = 0
+			}
+			log.Printf("Car %s completed pit stop %d", state.CarId, state.PitStops)
+		}
+
+	default:
+		return false
+	}
+
+	state.Speed = 0
+	return true
+}
+
+// A car driving out of the speed limit through the exit has served a
+// drive-through it went in with
+func (st *RaceState) leavePitLane(state *CarStateExtended, throughExit bool) {
+	log.Printf("Car %s leaves the pit lane", state.CarId)
+
+	penalty, hasPenalty := st.penalties[state.CarId]
+	if throughExit && hasPenalty && penalty.Kind == pb.PenaltyKind_PENALTY_DRIVE_THROUGH {
+		delete(st.penalties, state.CarId)
+		log.Printf("Car %s served its drive-through penalty", state.CarId)
+	}
+}
+
+// Race rules checked at the flag: a pit lane penalty still to serve is added
+// to the car's race time, a car missing a mandatory stop is disqualified
+func (st *RaceState) checkRaceRules(state *CarStateExtended) {
+	if isTimedSession(st.config.setup.RaceType) || st.config.pitLane == nil {
+		return
+	}
+
+	if penalty, hasPenalty := st.penalties[state.CarId]; hasPenalty && penalty.Kind != pb.PenaltyKind_PENALTY_TIME {
+		state.timeAdded = penalty.RemainingPenalty
+		if penalty.Kind == pb.PenaltyKind_PENALTY_DRIVE_THROUGH {
+			state.timeAdded += driveThroughTime
+		}
+		delete(st.penalties, state.CarId)
+		log.Printf("Car %s did not serve a %v penalty, %.1fs added to its race time",
+			state.CarId, penalty.Kind, float32(state.timeAdded)/1000)
+	}
+
+	mandatory := st.config.setup.MandatoryStops
+	if state.PitStops < mandatory {
+		state.disqualified = fmt.Sprintf("made %d of %d mandatory pit stops", state.PitStops, mandatory)
+		log.Printf("Car %s disqualified: %s", state.CarId, state.disqualified)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	pb "server/proto"
+)
+
+func TestPenaltyKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		track string
+		want  pb.PenaltyKind
+	}{
+		{"pit lane", defaultTrack, pb.PenaltyKind_PENALTY_STOP_GO},
+		{"no pit lane", generatedTrack, pb.PenaltyKind_PENALTY_TIME},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setup := raceSetup{Seed: 1, RaceType: pb.RaceType_RACEBYLAPS, NumCars: 1, Laps: 1}
+			if err := setup.selectTrack(test.track, nil); err != nil {
+				t.Fatal(err)
+			}
+			config, err := newRaceConfig(setup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st := newRaceState(config)
+			if (config.pitLane != nil) != (test.want != pb.PenaltyKind_PENALTY_TIME) {
+				t.Fatalf("pit lane %v on %s", config.pitLane != nil, test.track)
+			}
+
+			state := st.carStates["A"]
+			state.Status = pb.CarStatus_RACING
+			st.issuePenalty(state, "test", 5000)
+			if got := st.penalties["A"].Kind; got != test.want {
+				t.Errorf("penalty %v, want %v", got, test.want)
+			}
+			// Only a penalty served in place stops the car on track
+			if stopped := state.Status == pb.CarStatus_SERVINGPENALTY; stopped != (test.want == pb.PenaltyKind_PENALTY_TIME) {
+				t.Errorf("car %v with a %v penalty", state.Status, test.want)
+			}
+		})
+	}
+}
+
+func TestUnservedPenaltyAddsTime(t *testing.T) {
+	setup := raceSetup{Seed: 1, RaceType: pb.RaceType_RACEBYLAPS, NumCars: 2, Laps: 1}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	config, err := newRaceConfig(setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := newRaceState(config)
+	st.raceStartTick = 100
+	n := int32(len(config.centerline))
+	for _, state := range st.carStates {
+		state.Status = pb.CarStatus_RACING
+		state.checkedIn = true
+		state.covered, state.distance = n, n
+	}
+	a, b := st.carStates["A"], st.carStates["B"]
+
+	// A takes the flag a second ahead of B with a 5 second stop-go to serve
+	st.issuePenalty(a, "test", 5000)
+	st.gameTick = 1000
+	st.finishCar(a)
+	st.gameTick = 1060
+	st.finishCar(b)
+
+	if a.disqualified != "" {
+		t.Errorf("car A disqualified: %s", a.disqualified)
+	}
+	if _, ok := st.penalties["A"]; ok || a.timeAdded != 5000 {
+		t.Errorf("%dms added to car A, want the 5000ms penalty", a.timeAdded)
+	}
+	if want := 900*fixedDt + 5; st.totalTime(a) != want {
+		t.Errorf("car A race time %v, want %v", st.totalTime(a), want)
+	}
+	if order := st.raceOrder(); order[0] != "B" || order[1] != "A" {
+		t.Errorf("race order %v, want B ahead", order)
+	}
+}
@@ -21,6 +21,104 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Surface int32
+
+const (
+	Surface_SURFACE_ASPHALT Surface = 0 // The track itself
+	Surface_SURFACE_KERB    Surface = 1
+	Surface_SURFACE_GRASS   Surface = 2
+	Surface_SURFACE_GRAVEL  Surface = 3
+)
+
+// Enum value maps for Surface.
+var (
+	Surface_name = map[int32]string{
+		0: "SURFACE_ASPHALT",
+		1: "SURFACE_KERB",
+		2: "SURFACE_GRASS",
+		3: "SURFACE_GRAVEL",
+	}
+	Surface_value = map[string]int32{
+		"SURFACE_ASPHALT": 0,
+		"SURFACE_KERB":    1,
+		"SURFACE_GRASS":   2,
+		"SURFACE_GRAVEL":  3,
+	}
+)
+
+func (x Surface) Enum() *Surface {
+	p := new(Surface)
+	*p = x
+	return p
+}
+
+func (x Surface) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Surface) Descriptor() protoreflect.EnumDescriptor {
+	return file_car_proto_enumTypes[0].Descriptor()
+}
+
+func (Surface) Type() protoreflect.EnumType {
+	return &file_car_proto_enumTypes[0]
+}
+
+func (x Surface) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Surface.Descriptor instead.
+func (Surface) EnumDescriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{0}
+}
+
+type TrackDirection int32
+
+const (
+	TrackDirection_DIRECTION_CLOCKWISE        TrackDirection = 0
+	TrackDirection_DIRECTION_COUNTERCLOCKWISE TrackDirection = 1
+)
+
+// Enum value maps for TrackDirection.
+var (
+	TrackDirection_name = map[int32]string{
+		0: "DIRECTION_CLOCKWISE",
+		1: "DIRECTION_COUNTERCLOCKWISE",
+	}
+	TrackDirection_value = map[string]int32{
+		"DIRECTION_CLOCKWISE":        0,
+		"DIRECTION_COUNTERCLOCKWISE": 1,
+	}
+)
+
+func (x TrackDirection) Enum() *TrackDirection {
+	p := new(TrackDirection)
+	*p = x
+	return p
+}
+
+func (x TrackDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TrackDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_car_proto_enumTypes[1].Descriptor()
+}
+
+func (TrackDirection) Type() protoreflect.EnumType {
+	return &file_car_proto_enumTypes[1]
+}
+
+func (x TrackDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TrackDirection.Descriptor instead.
+func (TrackDirection) EnumDescriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{1}
+}
+
 type RaceType int32
 
 const (
@@ -57,11 +155,11 @@ func (x RaceType) String() string {
 }
 
 func (RaceType) Descriptor() protoreflect.EnumDescriptor {
-	return file_car_proto_enumTypes[0].Descriptor()
+	return file_car_proto_enumTypes[2].Descriptor()
 }
 
 func (RaceType) Type() protoreflect.EnumType {
-	return &file_car_proto_enumTypes[0]
+	return &file_car_proto_enumTypes[2]
 }
 
 func (x RaceType) Number() protoreflect.EnumNumber {
@@ -70,7 +168,57 @@ func (x RaceType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RaceType.Descriptor instead.
 func (RaceType) EnumDescriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{0}
+	return file_car_proto_rawDescGZIP(), []int{2}
+}
+
+// How the server drives the simulation
+type SimulationMode int32
+
+const (
+	SimulationMode_REALTIME SimulationMode = 0 // One tick per 1/60 s of wall-clock time
+	SimulationMode_FAST     SimulationMode = 1 // Headless, ticks as fast as the CPU allows
+	SimulationMode_LOCKSTEP SimulationMode = 2 // Headless, ticks once every checked-in car has sent input
+)
+
+// Enum value maps for SimulationMode.
+var (
+	SimulationMode_name = map[int32]string{
+		0: "REALTIME",
+		1: "FAST",
+		2: "LOCKSTEP",
+	}
+	SimulationMode_value = map[string]int32{
+		"REALTIME": 0,
+		"FAST":     1,
+		"LOCKSTEP": 2,
+	}
+)
+
+func (x SimulationMode) Enum() *SimulationMode {
+	p := new(SimulationMode)
+	*p = x
+	return p
+}
+
+func (x SimulationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SimulationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_car_proto_enumTypes[3].Descriptor()
+}
+
+func (SimulationMode) Type() protoreflect.EnumType {
+	return &file_car_proto_enumTypes[3]
+}
+
+func (x SimulationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SimulationMode.Descriptor instead.
+func (SimulationMode) EnumDescriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{3}
 }
 
 type CarStatus int32
@@ -80,6 +228,7 @@ const (
 	CarStatus_WAITING        CarStatus = 1
 	CarStatus_RACING         CarStatus = 2
 	CarStatus_SERVINGPENALTY CarStatus = 3
+	CarStatus_INPITS         CarStatus = 4 // In the speed-limited section of the pit lane
 	CarStatus_FINISHED       CarStatus = 99
 )
 
@@ -90,6 +239,7 @@ var (
 		1:  "WAITING",
 		2:  "RACING",
 		3:  "SERVINGPENALTY",
+		4:  "INPITS",
 		99: "FINISHED",
 	}
 	CarStatus_value = map[string]int32{
@@ -97,6 +247,7 @@ var (
 		"WAITING":        1,
 		"RACING":         2,
 		"SERVINGPENALTY": 3,
+		"INPITS":         4,
 		"FINISHED":       99,
 	}
 )
@@ -112,11 +263,11 @@ func (x CarStatus) String() string {
 }
 
 func (CarStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_car_proto_enumTypes[1].Descriptor()
+	return file_car_proto_enumTypes[4].Descriptor()
 }
 
 func (CarStatus) Type() protoreflect.EnumType {
-	return &file_car_proto_enumTypes[1]
+	return &file_car_proto_enumTypes[4]
 }
 
 func (x CarStatus) Number() protoreflect.EnumNumber {
@@ -125,7 +276,123 @@ func (x CarStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CarStatus.Descriptor instead.
 func (CarStatus) EnumDescriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{1}
+	return file_car_proto_rawDescGZIP(), []int{4}
+}
+
+// How a penalty is served
+type PenaltyKind int32
+
+const (
+	PenaltyKind_PENALTY_TIME          PenaltyKind = 0 // Stopped where the car is, unless the session serves penalties in the pit lane
+	PenaltyKind_PENALTY_DRIVE_THROUGH PenaltyKind = 1 // Drive through the pit lane without stopping
+	PenaltyKind_PENALTY_STOP_GO       PenaltyKind = 2 // Stop in the pit box for remaining_penalty, no service
+)
+
+// Enum value maps for PenaltyKind.
+var (
+	PenaltyKind_name = map[int32]string{
+		0: "PENALTY_TIME",
+		1: "PENALTY_DRIVE_THROUGH",
+		2: "PENALTY_STOP_GO",
+	}
+	PenaltyKind_value = map[string]int32{
+		"PENALTY_TIME":          0,
+		"PENALTY_DRIVE_THROUGH": 1,
+		"PENALTY_STOP_GO":       2,
+	}
+)
+
+func (x PenaltyKind) Enum() *PenaltyKind {
+	p := new(PenaltyKind)
+	*p = x
+	return p
+}
+
+func (x PenaltyKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PenaltyKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_car_proto_enumTypes[5].Descriptor()
+}
+
+func (PenaltyKind) Type() protoreflect.EnumType {
+	return &file_car_proto_enumTypes[5]
+}
+
+func (x PenaltyKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PenaltyKind.Descriptor instead.
+func (PenaltyKind) EnumDescriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{5}
+}
+
+// ---------------------------------------------------
+// Race lifecycle, in order
+type RacePhase int32
+
+const (
+	RacePhase_PHASE_NOTREADY     RacePhase = 0 // Session created, not started
+	RacePhase_PHASE_WAITING      RacePhase = 1 // Cars are checking in
+	RacePhase_PHASE_GRID         RacePhase = 2 // Cars lined up on the grid
+	RacePhase_PHASE_START_LIGHTS RacePhase = 3 // Start light countdown, inputs now are jump starts
+	RacePhase_PHASE_RACING       RacePhase = 4 // Lights out, or session running
+	RacePhase_PHASE_CHEQUERED    RacePhase = 5 // Flag is out, cars finish at their next line crossing
+	RacePhase_PHASE_COOLDOWN     RacePhase = 6 // Everyone has finished
+	RacePhase_PHASE_RESULTS      RacePhase = 7 // Final classification
+)
+
+// Enum value maps for RacePhase.
+var (
+	RacePhase_name = map[int32]string{
+		0: "PHASE_NOTREADY",
+		1: "PHASE_WAITING",
+		2: "PHASE_GRID",
+		3: "PHASE_START_LIGHTS",
+		4: "PHASE_RACING",
+		5: "PHASE_CHEQUERED",
+		6: "PHASE_COOLDOWN",
+		7: "PHASE_RESULTS",
+	}
+	RacePhase_value = map[string]int32{
+		"PHASE_NOTREADY":     0,
+		"PHASE_WAITING":      1,
+		"PHASE_GRID":         2,
+		"PHASE_START_LIGHTS": 3,
+		"PHASE_RACING":       4,
+		"PHASE_CHEQUERED":    5,
+		"PHASE_COOLDOWN":     6,
+		"PHASE_RESULTS":      7,
+	}
+)
+
+func (x RacePhase) Enum() *RacePhase {
+	p := new(RacePhase)
+	*p = x
+	return p
+}
+
+func (x RacePhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RacePhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_car_proto_enumTypes[6].Descriptor()
+}
+
+func (RacePhase) Type() protoreflect.EnumType {
+	return &file_car_proto_enumTypes[6]
+}
+
+func (x RacePhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RacePhase.Descriptor instead.
+func (RacePhase) EnumDescriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{6}
 }
 
 // ---------------------------------------------------
@@ -166,6 +433,51 @@ func (*Empty) Descriptor() ([]byte, []int) {
 	return file_car_proto_rawDescGZIP(), []int{0}
 }
 
+// Wire compatible with Empty, for clients of the single-session server
+type SessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_car_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
 // ---------------------------------------------------
 // 3D point for track boundaries (32-bit floats)
 type Point3D struct {
@@ -179,7 +491,7 @@ type Point3D struct {
 
 func (x *Point3D) Reset() {
 	*x = Point3D{}
-	mi := &file_car_proto_msgTypes[1]
+	mi := &file_car_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -191,7 +503,7 @@ func (x *Point3D) String() string {
 func (*Point3D) ProtoMessage() {}
 
 func (x *Point3D) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[1]
+	mi := &file_car_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -204,7 +516,7 @@ func (x *Point3D) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Point3D.ProtoReflect.Descriptor instead.
 func (*Point3D) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{1}
+	return file_car_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Point3D) GetX() float32 {
@@ -236,13 +548,16 @@ type TrackInfo struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	LeftBoundary  []*Point3D             `protobuf:"bytes,3,rep,name=left_boundary,json=leftBoundary,proto3" json:"left_boundary,omitempty"`    // Left edge of track
 	RightBoundary []*Point3D             `protobuf:"bytes,4,rep,name=right_boundary,json=rightBoundary,proto3" json:"right_boundary,omitempty"` // Right edge of track
+	PitLane       *PitLane               `protobuf:"bytes,5,opt,name=pit_lane,json=pitLane,proto3" json:"pit_lane,omitempty"`                   // Unset when the track has no pit lane
+	Zones         []*SurfaceZone         `protobuf:"bytes,6,rep,name=zones,proto3" json:"zones,omitempty"`                                      // Run-off beyond the boundaries, anything further out is grass
+	Surfaces      []*SurfaceProperties   `protobuf:"bytes,7,rep,name=surfaces,proto3" json:"surfaces,omitempty"`                                // How each surface drives
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TrackInfo) Reset() {
 	*x = TrackInfo{}
-	mi := &file_car_proto_msgTypes[2]
+	mi := &file_car_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -254,7 +569,7 @@ func (x *TrackInfo) String() string {
 func (*TrackInfo) ProtoMessage() {}
 
 func (x *TrackInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[2]
+	mi := &file_car_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,7 +582,7 @@ func (x *TrackInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TrackInfo.ProtoReflect.Descriptor instead.
 func (*TrackInfo) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{2}
+	return file_car_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *TrackInfo) GetTrackId() string {
@@ -298,30 +613,57 @@ func (x *TrackInfo) GetRightBoundary() []*Point3D {
 	return nil
 }
 
-type RaceDescription struct {
+func (x *TrackInfo) GetPitLane() *PitLane {
+	if x != nil {
+		return x.PitLane
+	}
+	return nil
+}
+
+func (x *TrackInfo) GetZones() []*SurfaceZone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *TrackInfo) GetSurfaces() []*SurfaceProperties {
+	if x != nil {
+		return x.Surfaces
+	}
+	return nil
+}
+
+// A strip of one surface beside one edge of the track, from boundary point
+// start to boundary point end inclusive. A zone with end < start runs on
+// past the start line.
+type SurfaceZone struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Racetype      RaceType               `protobuf:"varint,1,opt,name=racetype,proto3,enum=car.RaceType" json:"racetype,omitempty"`
-	Laps          int32                  `protobuf:"varint,100,opt,name=laps,proto3" json:"laps,omitempty"`
-	Time          int32                  `protobuf:"varint,101,opt,name=time,proto3" json:"time,omitempty"`
+	Surface       Surface                `protobuf:"varint,1,opt,name=surface,proto3,enum=car.Surface" json:"surface,omitempty"`
+	Left          bool                   `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"` // Beside the left boundary, else the right
+	Start         int32                  `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+	Inner         float32                `protobuf:"fixed32,5,opt,name=inner,proto3" json:"inner,omitempty"` // Metres beyond the boundary where the strip starts
+	Outer         float32                `protobuf:"fixed32,6,opt,name=outer,proto3" json:"outer,omitempty"` // Metres beyond the boundary where it ends
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RaceDescription) Reset() {
-	*x = RaceDescription{}
-	mi := &file_car_proto_msgTypes[3]
+func (x *SurfaceZone) Reset() {
+	*x = SurfaceZone{}
+	mi := &file_car_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RaceDescription) String() string {
+func (x *SurfaceZone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RaceDescription) ProtoMessage() {}
+func (*SurfaceZone) ProtoMessage() {}
 
-func (x *RaceDescription) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[3]
+func (x *SurfaceZone) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,58 +674,77 @@ func (x *RaceDescription) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RaceDescription.ProtoReflect.Descriptor instead.
-func (*RaceDescription) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SurfaceZone.ProtoReflect.Descriptor instead.
+func (*SurfaceZone) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RaceDescription) GetRacetype() RaceType {
+func (x *SurfaceZone) GetSurface() Surface {
 	if x != nil {
-		return x.Racetype
+		return x.Surface
 	}
-	return RaceType_HOTLAP
+	return Surface_SURFACE_ASPHALT
 }
 
-func (x *RaceDescription) GetLaps() int32 {
+func (x *SurfaceZone) GetLeft() bool {
 	if x != nil {
-		return x.Laps
+		return x.Left
+	}
+	return false
+}
+
+func (x *SurfaceZone) GetStart() int32 {
+	if x != nil {
+		return x.Start
 	}
 	return 0
 }
 
-func (x *RaceDescription) GetTime() int32 {
+func (x *SurfaceZone) GetEnd() int32 {
 	if x != nil {
-		return x.Time
+		return x.End
 	}
 	return 0
 }
 
-// ---------------------------------------------------
-// Static car information
-type CarInfo struct {
+func (x *SurfaceZone) GetInner() float32 {
+	if x != nil {
+		return x.Inner
+	}
+	return 0
+}
+
+func (x *SurfaceZone) GetOuter() float32 {
+	if x != nil {
+		return x.Outer
+	}
+	return 0
+}
+
+type SurfaceProperties struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	Power         float32                `protobuf:"fixed32,3,opt,name=power,proto3" json:"power,omitempty"` // Engine power (0-100 scale)
-	Weight        float32                `protobuf:"fixed32,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	Surface       Surface                `protobuf:"varint,1,opt,name=surface,proto3,enum=car.Surface" json:"surface,omitempty"`
+	Friction      float32                `protobuf:"fixed32,2,opt,name=friction,proto3" json:"friction,omitempty"` // Multiplies tyre grip
+	Drag          float32                `protobuf:"fixed32,3,opt,name=drag,proto3" json:"drag,omitempty"`         // Rolling drag as a fraction of the car's weight, on top of the usual
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CarInfo) Reset() {
-	*x = CarInfo{}
-	mi := &file_car_proto_msgTypes[4]
+func (x *SurfaceProperties) Reset() {
+	*x = SurfaceProperties{}
+	mi := &file_car_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CarInfo) String() string {
+func (x *SurfaceProperties) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CarInfo) ProtoMessage() {}
+func (*SurfaceProperties) ProtoMessage() {}
 
-func (x *CarInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[4]
+func (x *SurfaceProperties) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -394,58 +755,63 @@ func (x *CarInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CarInfo.ProtoReflect.Descriptor instead.
-func (*CarInfo) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use SurfaceProperties.ProtoReflect.Descriptor instead.
+func (*SurfaceProperties) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CarInfo) GetCarId() string {
+func (x *SurfaceProperties) GetSurface() Surface {
 	if x != nil {
-		return x.CarId
+		return x.Surface
 	}
-	return ""
+	return Surface_SURFACE_ASPHALT
 }
 
-func (x *CarInfo) GetPower() float32 {
+func (x *SurfaceProperties) GetFriction() float32 {
 	if x != nil {
-		return x.Power
+		return x.Friction
 	}
 	return 0
 }
 
-func (x *CarInfo) GetWeight() float32 {
+func (x *SurfaceProperties) GetDrag() float32 {
 	if x != nil {
-		return x.Weight
+		return x.Drag
 	}
 	return 0
 }
 
-// ---------------------------------------------------
-// Player registration request
-type RegisterPlayer struct {
+// A track in the tracks directory, as listed by ListTracks
+type TrackSummary struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	PlayerName    string                 `protobuf:"bytes,2,opt,name=player_name,json=playerName,proto3" json:"player_name,omitempty"`
-	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	TrackId       string                 `protobuf:"bytes,1,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	File          string                 `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`       // File name in the tracks directory
+	Length        float32                `protobuf:"fixed32,5,opt,name=length,proto3" json:"length,omitempty"` // Metres along the centerline
+	Direction     TrackDirection         `protobuf:"varint,6,opt,name=direction,proto3,enum=car.TrackDirection" json:"direction,omitempty"`
+	StartLeft     *Point3D               `protobuf:"bytes,7,opt,name=start_left,json=startLeft,proto3" json:"start_left,omitempty"`    // Left end of the start/finish line
+	StartRight    *Point3D               `protobuf:"bytes,8,opt,name=start_right,json=startRight,proto3" json:"start_right,omitempty"` // Right end of the start/finish line
+	PitLane       bool                   `protobuf:"varint,9,opt,name=pit_lane,json=pitLane,proto3" json:"pit_lane,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterPlayer) Reset() {
-	*x = RegisterPlayer{}
-	mi := &file_car_proto_msgTypes[5]
+func (x *TrackSummary) Reset() {
+	*x = TrackSummary{}
+	mi := &file_car_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterPlayer) String() string {
+func (x *TrackSummary) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterPlayer) ProtoMessage() {}
+func (*TrackSummary) ProtoMessage() {}
 
-func (x *RegisterPlayer) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[5]
+func (x *TrackSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,61 +822,96 @@ func (x *RegisterPlayer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterPlayer.ProtoReflect.Descriptor instead.
-func (*RegisterPlayer) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use TrackSummary.ProtoReflect.Descriptor instead.
+func (*TrackSummary) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *RegisterPlayer) GetCarId() string {
+func (x *TrackSummary) GetTrackId() string {
 	if x != nil {
-		return x.CarId
+		return x.TrackId
 	}
 	return ""
 }
 
-func (x *RegisterPlayer) GetPlayerName() string {
+func (x *TrackSummary) GetName() string {
 	if x != nil {
-		return x.PlayerName
+		return x.Name
 	}
 	return ""
 }
 
-func (x *RegisterPlayer) GetPassword() string {
+func (x *TrackSummary) GetCountry() string {
 	if x != nil {
-		return x.Password
+		return x.Country
 	}
 	return ""
 }
 
-// ---------------------------------------------------
-// CheckIn response with ack, static data, and auth token
-type CheckInResponse struct {
+func (x *TrackSummary) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *TrackSummary) GetLength() float32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *TrackSummary) GetDirection() TrackDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return TrackDirection_DIRECTION_CLOCKWISE
+}
+
+func (x *TrackSummary) GetStartLeft() *Point3D {
+	if x != nil {
+		return x.StartLeft
+	}
+	return nil
+}
+
+func (x *TrackSummary) GetStartRight() *Point3D {
+	if x != nil {
+		return x.StartRight
+	}
+	return nil
+}
+
+func (x *TrackSummary) GetPitLane() bool {
+	if x != nil {
+		return x.PitLane
+	}
+	return false
+}
+
+type TrackList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
-	AuthToken     string                 `protobuf:"bytes,2,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`        // Token to use in PlayerInput (empty for spectators)
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                             // Error or success message
-	IsSpectator   bool                   `protobuf:"varint,4,opt,name=is_spectator,json=isSpectator,proto3" json:"is_spectator,omitempty"` // True if logged in as spectator
-	Track         *TrackInfo             `protobuf:"bytes,5,opt,name=track,proto3" json:"track,omitempty"`                                 // Track boundaries
-	Race          RaceType               `protobuf:"varint,6,opt,name=race,proto3,enum=car.RaceType" json:"race,omitempty"`
+	Tracks        []*TrackSummary        `protobuf:"bytes,1,rep,name=tracks,proto3" json:"tracks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckInResponse) Reset() {
-	*x = CheckInResponse{}
-	mi := &file_car_proto_msgTypes[6]
+func (x *TrackList) Reset() {
+	*x = TrackList{}
+	mi := &file_car_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckInResponse) String() string {
+func (x *TrackList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckInResponse) ProtoMessage() {}
+func (*TrackList) ProtoMessage() {}
 
-func (x *CheckInResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[6]
+func (x *TrackList) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,82 +922,120 @@ func (x *CheckInResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckInResponse.ProtoReflect.Descriptor instead.
-func (*CheckInResponse) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use TrackList.ProtoReflect.Descriptor instead.
+func (*TrackList) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *CheckInResponse) GetAccepted() bool {
+func (x *TrackList) GetTracks() []*TrackSummary {
 	if x != nil {
-		return x.Accepted
+		return x.Tracks
 	}
-	return false
+	return nil
 }
 
-func (x *CheckInResponse) GetAuthToken() string {
+// Pit lane, a lane from the entry to the exit running beside the track.
+// Cars stop in their pit box anywhere in the speed-limited section.
+type PitLane struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeftBoundary  []*Point3D             `protobuf:"bytes,1,rep,name=left_boundary,json=leftBoundary,proto3" json:"left_boundary,omitempty"`
+	RightBoundary []*Point3D             `protobuf:"bytes,2,rep,name=right_boundary,json=rightBoundary,proto3" json:"right_boundary,omitempty"`
+	SpeedLimit    float32                `protobuf:"fixed32,3,opt,name=speed_limit,json=speedLimit,proto3" json:"speed_limit,omitempty"`       // m/s
+	LimiterStart  float32                `protobuf:"fixed32,4,opt,name=limiter_start,json=limiterStart,proto3" json:"limiter_start,omitempty"` // Metres along the lane where the speed limit starts
+	LimiterEnd    float32                `protobuf:"fixed32,5,opt,name=limiter_end,json=limiterEnd,proto3" json:"limiter_end,omitempty"`       // Metres along the lane where it ends
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PitLane) Reset() {
+	*x = PitLane{}
+	mi := &file_car_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PitLane) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PitLane) ProtoMessage() {}
+
+func (x *PitLane) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[8]
 	if x != nil {
-		return x.AuthToken
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *CheckInResponse) GetMessage() string {
+// Deprecated: Use PitLane.ProtoReflect.Descriptor instead.
+func (*PitLane) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PitLane) GetLeftBoundary() []*Point3D {
 	if x != nil {
-		return x.Message
+		return x.LeftBoundary
 	}
-	return ""
+	return nil
 }
 
-func (x *CheckInResponse) GetIsSpectator() bool {
+func (x *PitLane) GetRightBoundary() []*Point3D {
 	if x != nil {
-		return x.IsSpectator
+		return x.RightBoundary
 	}
-	return false
+	return nil
 }
 
-func (x *CheckInResponse) GetTrack() *TrackInfo {
+func (x *PitLane) GetSpeedLimit() float32 {
 	if x != nil {
-		return x.Track
+		return x.SpeedLimit
 	}
-	return nil
+	return 0
 }
 
-func (x *CheckInResponse) GetRace() RaceType {
+func (x *PitLane) GetLimiterStart() float32 {
 	if x != nil {
-		return x.Race
+		return x.LimiterStart
 	}
-	return RaceType_HOTLAP
+	return 0
 }
 
-// ---------------------------------------------------
-// Player input controls
-type PlayerInput struct {
+func (x *PitLane) GetLimiterEnd() float32 {
+	if x != nil {
+		return x.LimiterEnd
+	}
+	return 0
+}
+
+type RaceDescription struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	AuthToken     string                 `protobuf:"bytes,2,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
-	Steering      float32                `protobuf:"fixed32,3,opt,name=steering,proto3" json:"steering,omitempty"` // -1.0 to 1.0
-	Throttle      float32                `protobuf:"fixed32,4,opt,name=throttle,proto3" json:"throttle,omitempty"` // 0.0 to 1.0
-	Brake         float32                `protobuf:"fixed32,5,opt,name=brake,proto3" json:"brake,omitempty"`       // 0.0 to 1.0
-	Timestamp     int32                  `protobuf:"varint,99,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Racetype      RaceType               `protobuf:"varint,1,opt,name=racetype,proto3,enum=car.RaceType" json:"racetype,omitempty"`
+	Laps          int32                  `protobuf:"varint,100,opt,name=laps,proto3" json:"laps,omitempty"`
+	Time          int32                  `protobuf:"varint,101,opt,name=time,proto3" json:"time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlayerInput) Reset() {
-	*x = PlayerInput{}
-	mi := &file_car_proto_msgTypes[7]
+func (x *RaceDescription) Reset() {
+	*x = RaceDescription{}
+	mi := &file_car_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayerInput) String() string {
+func (x *RaceDescription) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayerInput) ProtoMessage() {}
+func (*RaceDescription) ProtoMessage() {}
 
-func (x *PlayerInput) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[7]
+func (x *RaceDescription) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,79 +1046,2095 @@ func (x *PlayerInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayerInput.ProtoReflect.Descriptor instead.
-func (*PlayerInput) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RaceDescription.ProtoReflect.Descriptor instead.
+func (*RaceDescription) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PlayerInput) GetCarId() string {
+func (x *RaceDescription) GetRacetype() RaceType {
 	if x != nil {
-		return x.CarId
+		return x.Racetype
 	}
-	return ""
+	return RaceType_HOTLAP
 }
 
-func (x *PlayerInput) GetAuthToken() string {
+func (x *RaceDescription) GetLaps() int32 {
 	if x != nil {
-		return x.AuthToken
+		return x.Laps
+	}
+	return 0
+}
+
+func (x *RaceDescription) GetTime() int32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Static car information
+type CarInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CarId           string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	Power           float32                `protobuf:"fixed32,3,opt,name=power,proto3" json:"power,omitempty"`                                            // Engine power (0-100 scale)
+	Weight          float32                `protobuf:"fixed32,4,opt,name=weight,proto3" json:"weight,omitempty"`                                          // kg, without fuel
+	FuelCapacity    float32                `protobuf:"fixed32,5,opt,name=fuel_capacity,json=fuelCapacity,proto3" json:"fuel_capacity,omitempty"`          // kg
+	FuelConsumption float32                `protobuf:"fixed32,6,opt,name=fuel_consumption,json=fuelConsumption,proto3" json:"fuel_consumption,omitempty"` // kg per second at full throttle
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CarInfo) Reset() {
+	*x = CarInfo{}
+	mi := &file_car_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarInfo) ProtoMessage() {}
+
+func (x *CarInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarInfo.ProtoReflect.Descriptor instead.
+func (*CarInfo) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CarInfo) GetCarId() string {
+	if x != nil {
+		return x.CarId
 	}
 	return ""
 }
 
-func (x *PlayerInput) GetSteering() float32 {
+func (x *CarInfo) GetPower() float32 {
 	if x != nil {
-		return x.Steering
+		return x.Power
 	}
 	return 0
 }
 
-func (x *PlayerInput) GetThrottle() float32 {
+func (x *CarInfo) GetWeight() float32 {
 	if x != nil {
-		return x.Throttle
+		return x.Weight
 	}
 	return 0
 }
 
-func (x *PlayerInput) GetBrake() float32 {
+func (x *CarInfo) GetFuelCapacity() float32 {
 	if x != nil {
-		return x.Brake
+		return x.FuelCapacity
 	}
 	return 0
 }
 
-func (x *PlayerInput) GetTimestamp() int32 {
+func (x *CarInfo) GetFuelConsumption() float32 {
 	if x != nil {
-		return x.Timestamp
+		return x.FuelConsumption
 	}
 	return 0
 }
 
 // ---------------------------------------------------
-// Input acknowledgment
-type InputAck struct {
+// Player registration request
+type RegisterPlayer struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
-	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
-	GameLoop      int32                  `protobuf:"varint,3,opt,name=game_loop,json=gameLoop,proto3" json:"game_loop,omitempty"`
+	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	PlayerName    string                 `protobuf:"bytes,2,opt,name=player_name,json=playerName,proto3" json:"player_name,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InputAck) Reset() {
-	*x = InputAck{}
-	mi := &file_car_proto_msgTypes[8]
+func (x *RegisterPlayer) Reset() {
+	*x = RegisterPlayer{}
+	mi := &file_car_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPlayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPlayer) ProtoMessage() {}
+
+func (x *RegisterPlayer) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPlayer.ProtoReflect.Descriptor instead.
+func (*RegisterPlayer) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RegisterPlayer) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *RegisterPlayer) GetPlayerName() string {
+	if x != nil {
+		return x.PlayerName
+	}
+	return ""
+}
+
+func (x *RegisterPlayer) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RegisterPlayer) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// ---------------------------------------------------
+// CheckIn response with ack, static data, and auth token
+type CheckInResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	AuthToken     string                 `protobuf:"bytes,2,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`        // Token to use in PlayerInput (empty for spectators)
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                             // Error or success message
+	IsSpectator   bool                   `protobuf:"varint,4,opt,name=is_spectator,json=isSpectator,proto3" json:"is_spectator,omitempty"` // True if logged in as spectator
+	Track         *TrackInfo             `protobuf:"bytes,5,opt,name=track,proto3" json:"track,omitempty"`                                 // Track boundaries
+	Race          RaceType               `protobuf:"varint,6,opt,name=race,proto3,enum=car.RaceType" json:"race,omitempty"`
+	Cars          []*CarInfo             `protobuf:"bytes,7,rep,name=cars,proto3" json:"cars,omitempty"` // Specs of every car in the race
+	SimMode       SimulationMode         `protobuf:"varint,8,opt,name=sim_mode,json=simMode,proto3,enum=car.SimulationMode" json:"sim_mode,omitempty"`
+	SessionId     string                 `protobuf:"bytes,9,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	CarId         string                 `protobuf:"bytes,10,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"` // The car checked in to, useful after JoinSession
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckInResponse) Reset() {
+	*x = CheckInResponse{}
+	mi := &file_car_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckInResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckInResponse) ProtoMessage() {}
+
+func (x *CheckInResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckInResponse.ProtoReflect.Descriptor instead.
+func (*CheckInResponse) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CheckInResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *CheckInResponse) GetAuthToken() string {
+	if x != nil {
+		return x.AuthToken
+	}
+	return ""
+}
+
+func (x *CheckInResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CheckInResponse) GetIsSpectator() bool {
+	if x != nil {
+		return x.IsSpectator
+	}
+	return false
+}
+
+func (x *CheckInResponse) GetTrack() *TrackInfo {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *CheckInResponse) GetRace() RaceType {
+	if x != nil {
+		return x.Race
+	}
+	return RaceType_HOTLAP
+}
+
+func (x *CheckInResponse) GetCars() []*CarInfo {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *CheckInResponse) GetSimMode() SimulationMode {
+	if x != nil {
+		return x.SimMode
+	}
+	return SimulationMode_REALTIME
+}
+
+func (x *CheckInResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CheckInResponse) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+// ---------------------------------------------------
+// Player input controls
+type PlayerInput struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	CarId     string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	AuthToken string                 `protobuf:"bytes,2,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
+	Steering  float32                `protobuf:"fixed32,3,opt,name=steering,proto3" json:"steering,omitempty"` // -1.0 to 1.0
+	Throttle  float32                `protobuf:"fixed32,4,opt,name=throttle,proto3" json:"throttle,omitempty"` // 0.0 to 1.0
+	Brake     float32                `protobuf:"fixed32,5,opt,name=brake,proto3" json:"brake,omitempty"`       // 0.0 to 1.0
+	SessionId string                 `protobuf:"bytes,6,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	PitRefuel float32                `protobuf:"fixed32,7,opt,name=pit_refuel,json=pitRefuel,proto3" json:"pit_refuel,omitempty"` // kg of fuel to take on at the next service stop
+	PitTyres  bool                   `protobuf:"varint,8,opt,name=pit_tyres,json=pitTyres,proto3" json:"pit_tyres,omitempty"`     // Fit new tyres at the next service stop
+	// Increases with every input the car sends, inputs no newer than the one
+	// in use are discarded. Counting may start over at check-in, on each Drive
+	// stream and with each race. 0 means unsequenced, is always taken and
+	// leaves the latest sequence in place.
+	Sequence uint64 `protobuf:"varint,9,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Tick the input is for, one past the latest update seen. Inputs for a tick
+	// older than the one in use, or past the next tick, are discarded. 0 means
+	// the next tick.
+	TargetTick int32 `protobuf:"varint,10,opt,name=target_tick,json=targetTick,proto3" json:"target_tick,omitempty"`
+	// Deprecated: Marked as deprecated in car.proto.
+	Timestamp     int32 `protobuf:"varint,99,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Ignored, sequence and target_tick replace it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerInput) Reset() {
+	*x = PlayerInput{}
+	mi := &file_car_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInput) ProtoMessage() {}
+
+func (x *PlayerInput) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInput.ProtoReflect.Descriptor instead.
+func (*PlayerInput) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PlayerInput) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *PlayerInput) GetAuthToken() string {
+	if x != nil {
+		return x.AuthToken
+	}
+	return ""
+}
+
+func (x *PlayerInput) GetSteering() float32 {
+	if x != nil {
+		return x.Steering
+	}
+	return 0
+}
+
+func (x *PlayerInput) GetThrottle() float32 {
+	if x != nil {
+		return x.Throttle
+	}
+	return 0
+}
+
+func (x *PlayerInput) GetBrake() float32 {
+	if x != nil {
+		return x.Brake
+	}
+	return 0
+}
+
+func (x *PlayerInput) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PlayerInput) GetPitRefuel() float32 {
+	if x != nil {
+		return x.PitRefuel
+	}
+	return 0
+}
+
+func (x *PlayerInput) GetPitTyres() bool {
+	if x != nil {
+		return x.PitTyres
+	}
+	return false
+}
+
+func (x *PlayerInput) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PlayerInput) GetTargetTick() int32 {
+	if x != nil {
+		return x.TargetTick
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in car.proto.
+func (x *PlayerInput) GetTimestamp() int32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Input acknowledgment
+type InputAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	GameLoop      int32                  `protobuf:"varint,3,opt,name=game_loop,json=gameLoop,proto3" json:"game_loop,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"` // Sequence of the input in use, older than the one sent when that was stale
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputAck) Reset() {
+	*x = InputAck{}
+	mi := &file_car_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputAck) ProtoMessage() {}
+
+func (x *InputAck) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputAck.ProtoReflect.Descriptor instead.
+func (*InputAck) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *InputAck) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *InputAck) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *InputAck) GetGameLoop() int32 {
+	if x != nil {
+		return x.GameLoop
+	}
+	return 0
+}
+
+func (x *InputAck) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Per-tick acknowledgment on the Drive stream
+type DriveUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameTick      int32                  `protobuf:"varint,1,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"` // Latest input sequence the tick was stepped with, 0 before any
+	Car           *CarState              `protobuf:"bytes,3,opt,name=car,proto3" json:"car,omitempty"`            // The driving car after the tick
+	Phase         RacePhase              `protobuf:"varint,4,opt,name=phase,proto3,enum=car.RacePhase" json:"phase,omitempty"`
+	Paused        bool                   `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriveUpdate) Reset() {
+	*x = DriveUpdate{}
+	mi := &file_car_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriveUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriveUpdate) ProtoMessage() {}
+
+func (x *DriveUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriveUpdate.ProtoReflect.Descriptor instead.
+func (*DriveUpdate) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DriveUpdate) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *DriveUpdate) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DriveUpdate) GetCar() *CarState {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+func (x *DriveUpdate) GetPhase() RacePhase {
+	if x != nil {
+		return x.Phase
+	}
+	return RacePhase_PHASE_NOTREADY
+}
+
+func (x *DriveUpdate) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+// ---------------------------------------------------
+// Dynamic car state during race
+type CarState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	Status        CarStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=car.CarStatus" json:"status,omitempty"`
+	Position      *Point3D               `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Heading       float32                `protobuf:"fixed32,4,opt,name=heading,proto3" json:"heading,omitempty"`
+	Speed         float32                `protobuf:"fixed32,5,opt,name=speed,proto3" json:"speed,omitempty"`
+	Lap           int32                  `protobuf:"varint,6,opt,name=lap,proto3" json:"lap,omitempty"`
+	WrongWay      bool                   `protobuf:"varint,7,opt,name=wrong_way,json=wrongWay,proto3" json:"wrong_way,omitempty"`             // Driving against the direction of the track
+	PitStops      int32                  `protobuf:"varint,8,opt,name=pit_stops,json=pitStops,proto3" json:"pit_stops,omitempty"`             // Service stops completed
+	PitStopLeft   float32                `protobuf:"fixed32,9,opt,name=pit_stop_left,json=pitStopLeft,proto3" json:"pit_stop_left,omitempty"` // Seconds until a car stopped in its pit box is released
+	TyreWear      float32                `protobuf:"fixed32,10,opt,name=tyre_wear,json=tyreWear,proto3" json:"tyre_wear,omitempty"`           // 0 for new tyres, 1 for worn out
+	Fuel          float32                `protobuf:"fixed32,11,opt,name=fuel,proto3" json:"fuel,omitempty"`                                   // kg on board
+	Slipstream    float32                `protobuf:"fixed32,12,opt,name=slipstream,proto3" json:"slipstream,omitempty"`                       // Fraction of drag saved behind another car
+	DirtyAir      float32                `protobuf:"fixed32,13,opt,name=dirty_air,json=dirtyAir,proto3" json:"dirty_air,omitempty"`           // Fraction of cornering grip lost behind another car
+	Surface       Surface                `protobuf:"varint,14,opt,name=surface,proto3,enum=car.Surface" json:"surface,omitempty"`             // What the car is driving on
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarState) Reset() {
+	*x = CarState{}
+	mi := &file_car_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarState) ProtoMessage() {}
+
+func (x *CarState) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarState.ProtoReflect.Descriptor instead.
+func (*CarState) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CarState) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *CarState) GetStatus() CarStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CarStatus_NOTREADY
+}
+
+func (x *CarState) GetPosition() *Point3D {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *CarState) GetHeading() float32 {
+	if x != nil {
+		return x.Heading
+	}
+	return 0
+}
+
+func (x *CarState) GetSpeed() float32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *CarState) GetLap() int32 {
+	if x != nil {
+		return x.Lap
+	}
+	return 0
+}
+
+func (x *CarState) GetWrongWay() bool {
+	if x != nil {
+		return x.WrongWay
+	}
+	return false
+}
+
+func (x *CarState) GetPitStops() int32 {
+	if x != nil {
+		return x.PitStops
+	}
+	return 0
+}
+
+func (x *CarState) GetPitStopLeft() float32 {
+	if x != nil {
+		return x.PitStopLeft
+	}
+	return 0
+}
+
+func (x *CarState) GetTyreWear() float32 {
+	if x != nil {
+		return x.TyreWear
+	}
+	return 0
+}
+
+func (x *CarState) GetFuel() float32 {
+	if x != nil {
+		return x.Fuel
+	}
+	return 0
+}
+
+func (x *CarState) GetSlipstream() float32 {
+	if x != nil {
+		return x.Slipstream
+	}
+	return 0
+}
+
+func (x *CarState) GetDirtyAir() float32 {
+	if x != nil {
+		return x.DirtyAir
+	}
+	return 0
+}
+
+func (x *CarState) GetSurface() Surface {
+	if x != nil {
+		return x.Surface
+	}
+	return Surface_SURFACE_ASPHALT
+}
+
+type CarPenalty struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CarId            string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	Reason           string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	GameTick         int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	RemainingPenalty int32                  `protobuf:"varint,4,opt,name=remaining_penalty,json=remainingPenalty,proto3" json:"remaining_penalty,omitempty"` // Milliseconds
+	Kind             PenaltyKind            `protobuf:"varint,5,opt,name=kind,proto3,enum=car.PenaltyKind" json:"kind,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CarPenalty) Reset() {
+	*x = CarPenalty{}
+	mi := &file_car_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarPenalty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarPenalty) ProtoMessage() {}
+
+func (x *CarPenalty) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarPenalty.ProtoReflect.Descriptor instead.
+func (*CarPenalty) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CarPenalty) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *CarPenalty) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CarPenalty) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *CarPenalty) GetRemainingPenalty() int32 {
+	if x != nil {
+		return x.RemainingPenalty
+	}
+	return 0
+}
+
+func (x *CarPenalty) GetKind() PenaltyKind {
+	if x != nil {
+		return x.Kind
+	}
+	return PenaltyKind_PENALTY_TIME
+}
+
+// ---------------------------------------------------
+// Race status information
+type RaceStatus struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Status            string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "waiting", "racing", "finished"
+	TotalLaps         int32                  `protobuf:"varint,2,opt,name=total_laps,json=totalLaps,proto3" json:"total_laps,omitempty"`
+	GameTick          int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	RaceType          RaceType               `protobuf:"varint,4,opt,name=race_type,json=raceType,proto3,enum=car.RaceType" json:"race_type,omitempty"` // Current session type
+	TimeLeft          int32                  `protobuf:"varint,5,opt,name=time_left,json=timeLeft,proto3" json:"time_left,omitempty"`                   // Seconds left in time-limited sessions
+	Phase             RacePhase              `protobuf:"varint,6,opt,name=phase,proto3,enum=car.RacePhase" json:"phase,omitempty"`
+	LightsOn          int32                  `protobuf:"varint,7,opt,name=lights_on,json=lightsOn,proto3" json:"lights_on,omitempty"`                              // Start lights lit during the countdown
+	Paused            bool                   `protobuf:"varint,8,opt,name=paused,proto3" json:"paused,omitempty"`                                                  // Race control has paused the session
+	MandatoryPitStops int32                  `protobuf:"varint,9,opt,name=mandatory_pit_stops,json=mandatoryPitStops,proto3" json:"mandatory_pit_stops,omitempty"` // Stops each car must make to be classified
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RaceStatus) Reset() {
+	*x = RaceStatus{}
+	mi := &file_car_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RaceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaceStatus) ProtoMessage() {}
+
+func (x *RaceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaceStatus.ProtoReflect.Descriptor instead.
+func (*RaceStatus) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RaceStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RaceStatus) GetTotalLaps() int32 {
+	if x != nil {
+		return x.TotalLaps
+	}
+	return 0
+}
+
+func (x *RaceStatus) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *RaceStatus) GetRaceType() RaceType {
+	if x != nil {
+		return x.RaceType
+	}
+	return RaceType_HOTLAP
+}
+
+func (x *RaceStatus) GetTimeLeft() int32 {
+	if x != nil {
+		return x.TimeLeft
+	}
+	return 0
+}
+
+func (x *RaceStatus) GetPhase() RacePhase {
+	if x != nil {
+		return x.Phase
+	}
+	return RacePhase_PHASE_NOTREADY
+}
+
+func (x *RaceStatus) GetLightsOn() int32 {
+	if x != nil {
+		return x.LightsOn
+	}
+	return 0
+}
+
+func (x *RaceStatus) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *RaceStatus) GetMandatoryPitStops() int32 {
+	if x != nil {
+		return x.MandatoryPitStops
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Contact between two cars
+type ContactEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarA          string                 `protobuf:"bytes,1,opt,name=car_a,json=carA,proto3" json:"car_a,omitempty"`
+	CarB          string                 `protobuf:"bytes,2,opt,name=car_b,json=carB,proto3" json:"car_b,omitempty"`
+	GameTick      int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Impulse       float32                `protobuf:"fixed32,4,opt,name=impulse,proto3" json:"impulse,omitempty"` // Collision impulse (kg*m/s)
+	Position      *Point3D               `protobuf:"bytes,5,opt,name=position,proto3" json:"position,omitempty"`
+	AtFault       string                 `protobuf:"bytes,6,opt,name=at_fault,json=atFault,proto3" json:"at_fault,omitempty"` // Car blamed by the stewards, empty for a racing incident
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContactEvent) Reset() {
+	*x = ContactEvent{}
+	mi := &file_car_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContactEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContactEvent) ProtoMessage() {}
+
+func (x *ContactEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContactEvent.ProtoReflect.Descriptor instead.
+func (*ContactEvent) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ContactEvent) GetCarA() string {
+	if x != nil {
+		return x.CarA
+	}
+	return ""
+}
+
+func (x *ContactEvent) GetCarB() string {
+	if x != nil {
+		return x.CarB
+	}
+	return ""
+}
+
+func (x *ContactEvent) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *ContactEvent) GetImpulse() float32 {
+	if x != nil {
+		return x.Impulse
+	}
+	return 0
+}
+
+func (x *ContactEvent) GetPosition() *Point3D {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *ContactEvent) GetAtFault() string {
+	if x != nil {
+		return x.AtFault
+	}
+	return ""
+}
+
+type CarInterval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Laps          int32                  `protobuf:"varint,3,opt,name=laps,proto3" json:"laps,omitempty"`
+	Interval      float32                `protobuf:"fixed32,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarInterval) Reset() {
+	*x = CarInterval{}
+	mi := &file_car_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarInterval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarInterval) ProtoMessage() {}
+
+func (x *CarInterval) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarInterval.ProtoReflect.Descriptor instead.
+func (*CarInterval) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CarInterval) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *CarInterval) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *CarInterval) GetLaps() int32 {
+	if x != nil {
+		return x.Laps
+	}
+	return 0
+}
+
+func (x *CarInterval) GetInterval() float32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Race update (streamed continuously)
+type RaceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RaceStatus    *RaceStatus            `protobuf:"bytes,1,opt,name=race_status,json=raceStatus,proto3" json:"race_status,omitempty"`
+	Cars          []*CarState            `protobuf:"bytes,2,rep,name=cars,proto3" json:"cars,omitempty"`
+	Penalties     []*CarPenalty          `protobuf:"bytes,3,rep,name=penalties,proto3" json:"penalties,omitempty"`
+	ToLeader      []*CarInterval         `protobuf:"bytes,4,rep,name=to_leader,json=toLeader,proto3" json:"to_leader,omitempty"`
+	ForPosition   []*CarInterval         `protobuf:"bytes,5,rep,name=for_position,json=forPosition,proto3" json:"for_position,omitempty"`
+	Contacts      []*ContactEvent        `protobuf:"bytes,6,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	Timing        *TimingInfo            `protobuf:"bytes,7,opt,name=timing,proto3" json:"timing,omitempty"`
+	GameTick      int32                  `protobuf:"varint,100,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RaceUpdate) Reset() {
+	*x = RaceUpdate{}
+	mi := &file_car_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RaceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaceUpdate) ProtoMessage() {}
+
+func (x *RaceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaceUpdate.ProtoReflect.Descriptor instead.
+func (*RaceUpdate) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RaceUpdate) GetRaceStatus() *RaceStatus {
+	if x != nil {
+		return x.RaceStatus
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetCars() []*CarState {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetPenalties() []*CarPenalty {
+	if x != nil {
+		return x.Penalties
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetToLeader() []*CarInterval {
+	if x != nil {
+		return x.ToLeader
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetForPosition() []*CarInterval {
+	if x != nil {
+		return x.ForPosition
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetContacts() []*ContactEvent {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetTiming() *TimingInfo {
+	if x != nil {
+		return x.Timing
+	}
+	return nil
+}
+
+func (x *RaceUpdate) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Lap and sector timing, times in seconds
+type CarTiming struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CarId             string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	CurrentSector     int32                  `protobuf:"varint,2,opt,name=current_sector,json=currentSector,proto3" json:"current_sector,omitempty"` // 0-based
+	CurrentLapTime    float32                `protobuf:"fixed32,3,opt,name=current_lap_time,json=currentLapTime,proto3" json:"current_lap_time,omitempty"`
+	LastLapTime       float32                `protobuf:"fixed32,4,opt,name=last_lap_time,json=lastLapTime,proto3" json:"last_lap_time,omitempty"`
+	BestLapTime       float32                `protobuf:"fixed32,5,opt,name=best_lap_time,json=bestLapTime,proto3" json:"best_lap_time,omitempty"` // Personal best, valid laps only
+	LapTimes          []float32              `protobuf:"fixed32,6,rep,packed,name=lap_times,json=lapTimes,proto3" json:"lap_times,omitempty"`
+	SectorTimes       []float32              `protobuf:"fixed32,7,rep,packed,name=sector_times,json=sectorTimes,proto3" json:"sector_times,omitempty"`               // Current lap so far
+	LastSectorTimes   []float32              `protobuf:"fixed32,8,rep,packed,name=last_sector_times,json=lastSectorTimes,proto3" json:"last_sector_times,omitempty"` // Last complete lap
+	BestSectorTimes   []float32              `protobuf:"fixed32,9,rep,packed,name=best_sector_times,json=bestSectorTimes,proto3" json:"best_sector_times,omitempty"` // Personal bests, 0 where not set
+	LapValid          bool                   `protobuf:"varint,10,opt,name=lap_valid,json=lapValid,proto3" json:"lap_valid,omitempty"`
+	GapToBestLap      float32                `protobuf:"fixed32,11,opt,name=gap_to_best_lap,json=gapToBestLap,proto3" json:"gap_to_best_lap,omitempty"`           // Personal best behind the overall best
+	NextCheckpoint    int32                  `protobuf:"varint,12,opt,name=next_checkpoint,json=nextCheckpoint,proto3" json:"next_checkpoint,omitempty"`          // Gate the car has to pass next, 0 is the start line
+	MissedCheckpoints int32                  `protobuf:"varint,13,opt,name=missed_checkpoints,json=missedCheckpoints,proto3" json:"missed_checkpoints,omitempty"` // Gates skipped in the session
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CarTiming) Reset() {
+	*x = CarTiming{}
+	mi := &file_car_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarTiming) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarTiming) ProtoMessage() {}
+
+func (x *CarTiming) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarTiming.ProtoReflect.Descriptor instead.
+func (*CarTiming) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CarTiming) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *CarTiming) GetCurrentSector() int32 {
+	if x != nil {
+		return x.CurrentSector
+	}
+	return 0
+}
+
+func (x *CarTiming) GetCurrentLapTime() float32 {
+	if x != nil {
+		return x.CurrentLapTime
+	}
+	return 0
+}
+
+func (x *CarTiming) GetLastLapTime() float32 {
+	if x != nil {
+		return x.LastLapTime
+	}
+	return 0
+}
+
+func (x *CarTiming) GetBestLapTime() float32 {
+	if x != nil {
+		return x.BestLapTime
+	}
+	return 0
+}
+
+func (x *CarTiming) GetLapTimes() []float32 {
+	if x != nil {
+		return x.LapTimes
+	}
+	return nil
+}
+
+func (x *CarTiming) GetSectorTimes() []float32 {
+	if x != nil {
+		return x.SectorTimes
+	}
+	return nil
+}
+
+func (x *CarTiming) GetLastSectorTimes() []float32 {
+	if x != nil {
+		return x.LastSectorTimes
+	}
+	return nil
+}
+
+func (x *CarTiming) GetBestSectorTimes() []float32 {
+	if x != nil {
+		return x.BestSectorTimes
+	}
+	return nil
+}
+
+func (x *CarTiming) GetLapValid() bool {
+	if x != nil {
+		return x.LapValid
+	}
+	return false
+}
+
+func (x *CarTiming) GetGapToBestLap() float32 {
+	if x != nil {
+		return x.GapToBestLap
+	}
+	return 0
+}
+
+func (x *CarTiming) GetNextCheckpoint() int32 {
+	if x != nil {
+		return x.NextCheckpoint
+	}
+	return 0
+}
+
+func (x *CarTiming) GetMissedCheckpoints() int32 {
+	if x != nil {
+		return x.MissedCheckpoints
+	}
+	return 0
+}
+
+type TimingInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Sectors          int32                  `protobuf:"varint,1,opt,name=sectors,proto3" json:"sectors,omitempty"`
+	SectorEnds       []float32              `protobuf:"fixed32,2,rep,packed,name=sector_ends,json=sectorEnds,proto3" json:"sector_ends,omitempty"` // Track progress (0-1] at the end of each sector
+	Cars             []*CarTiming           `protobuf:"bytes,3,rep,name=cars,proto3" json:"cars,omitempty"`
+	BestLapTime      float32                `protobuf:"fixed32,4,opt,name=best_lap_time,json=bestLapTime,proto3" json:"best_lap_time,omitempty"` // Overall best
+	BestLapCarId     string                 `protobuf:"bytes,5,opt,name=best_lap_car_id,json=bestLapCarId,proto3" json:"best_lap_car_id,omitempty"`
+	BestSectorTimes  []float32              `protobuf:"fixed32,6,rep,packed,name=best_sector_times,json=bestSectorTimes,proto3" json:"best_sector_times,omitempty"`
+	BestSectorCarIds []string               `protobuf:"bytes,7,rep,name=best_sector_car_ids,json=bestSectorCarIds,proto3" json:"best_sector_car_ids,omitempty"`
+	Checkpoints      []float32              `protobuf:"fixed32,8,rep,packed,name=checkpoints,proto3" json:"checkpoints,omitempty"` // Track progress [0-1) of each lap counting gate, the first is the start line
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TimingInfo) Reset() {
+	*x = TimingInfo{}
+	mi := &file_car_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimingInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimingInfo) ProtoMessage() {}
+
+func (x *TimingInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimingInfo.ProtoReflect.Descriptor instead.
+func (*TimingInfo) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TimingInfo) GetSectors() int32 {
+	if x != nil {
+		return x.Sectors
+	}
+	return 0
+}
+
+func (x *TimingInfo) GetSectorEnds() []float32 {
+	if x != nil {
+		return x.SectorEnds
+	}
+	return nil
+}
+
+func (x *TimingInfo) GetCars() []*CarTiming {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *TimingInfo) GetBestLapTime() float32 {
+	if x != nil {
+		return x.BestLapTime
+	}
+	return 0
+}
+
+func (x *TimingInfo) GetBestLapCarId() string {
+	if x != nil {
+		return x.BestLapCarId
+	}
+	return ""
+}
+
+func (x *TimingInfo) GetBestSectorTimes() []float32 {
+	if x != nil {
+		return x.BestSectorTimes
+	}
+	return nil
+}
+
+func (x *TimingInfo) GetBestSectorCarIds() []string {
+	if x != nil {
+		return x.BestSectorCarIds
+	}
+	return nil
+}
+
+func (x *TimingInfo) GetCheckpoints() []float32 {
+	if x != nil {
+		return x.Checkpoints
+	}
+	return nil
+}
+
+// ---------------------------------------------------
+// Results classification
+type ResultEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int32                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	CarId         string                 `protobuf:"bytes,2,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	Status        CarStatus              `protobuf:"varint,3,opt,name=status,proto3,enum=car.CarStatus" json:"status,omitempty"`
+	Laps          int32                  `protobuf:"varint,4,opt,name=laps,proto3" json:"laps,omitempty"`
+	TotalTime     float32                `protobuf:"fixed32,5,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"` // Seconds from the start, to the flag for finished cars; sum of laps in timed sessions
+	BestLapTime   float32                `protobuf:"fixed32,6,opt,name=best_lap_time,json=bestLapTime,proto3" json:"best_lap_time,omitempty"`
+	Gap           float32                `protobuf:"fixed32,7,opt,name=gap,proto3" json:"gap,omitempty"` // Seconds behind the winner, best lap gap in timed sessions
+	LapsBehind    int32                  `protobuf:"varint,8,opt,name=laps_behind,json=lapsBehind,proto3" json:"laps_behind,omitempty"`
+	Penalties     int32                  `protobuf:"varint,9,opt,name=penalties,proto3" json:"penalties,omitempty"`                          // Penalties applied
+	PenaltyTime   float32                `protobuf:"fixed32,10,opt,name=penalty_time,json=penaltyTime,proto3" json:"penalty_time,omitempty"` // Seconds of penalties applied
+	Disqualified  string                 `protobuf:"bytes,11,opt,name=disqualified,proto3" json:"disqualified,omitempty"`                    // Reason, empty for classified cars
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultEntry) Reset() {
+	*x = ResultEntry{}
+	mi := &file_car_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultEntry) ProtoMessage() {}
+
+func (x *ResultEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultEntry.ProtoReflect.Descriptor instead.
+func (*ResultEntry) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ResultEntry) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+func (x *ResultEntry) GetStatus() CarStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CarStatus_NOTREADY
+}
+
+func (x *ResultEntry) GetLaps() int32 {
+	if x != nil {
+		return x.Laps
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetTotalTime() float32 {
+	if x != nil {
+		return x.TotalTime
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetBestLapTime() float32 {
+	if x != nil {
+		return x.BestLapTime
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetGap() float32 {
+	if x != nil {
+		return x.Gap
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetLapsBehind() int32 {
+	if x != nil {
+		return x.LapsBehind
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetPenalties() int32 {
+	if x != nil {
+		return x.Penalties
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetPenaltyTime() float32 {
+	if x != nil {
+		return x.PenaltyTime
+	}
+	return 0
+}
+
+func (x *ResultEntry) GetDisqualified() string {
+	if x != nil {
+		return x.Disqualified
+	}
+	return ""
+}
+
+type Results struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	RaceType       RaceType               `protobuf:"varint,2,opt,name=race_type,json=raceType,proto3,enum=car.RaceType" json:"race_type,omitempty"`
+	Final          bool                   `protobuf:"varint,3,opt,name=final,proto3" json:"final,omitempty"` // The session is over
+	GameTick       int32                  `protobuf:"varint,4,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Classification []*ResultEntry         `protobuf:"bytes,5,rep,name=classification,proto3" json:"classification,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Results) Reset() {
+	*x = Results{}
+	mi := &file_car_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Results) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Results) ProtoMessage() {}
+
+func (x *Results) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Results.ProtoReflect.Descriptor instead.
+func (*Results) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Results) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Results) GetRaceType() RaceType {
+	if x != nil {
+		return x.RaceType
+	}
+	return RaceType_HOTLAP
+}
+
+func (x *Results) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *Results) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *Results) GetClassification() []*ResultEntry {
+	if x != nil {
+		return x.Classification
+	}
+	return nil
+}
+
+// ---------------------------------------------------
+// Race control messages
+type SessionConfig struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RaceType          RaceType               `protobuf:"varint,1,opt,name=race_type,json=raceType,proto3,enum=car.RaceType" json:"race_type,omitempty"`
+	Laps              int32                  `protobuf:"varint,2,opt,name=laps,proto3" json:"laps,omitempty"`
+	Time              int32                  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`                                                       // Seconds, for time-limited sessions
+	Track             string                 `protobuf:"bytes,4,opt,name=track,proto3" json:"track,omitempty"`                                                      // Track id or file name in the tracks directory, "generated" for a generated track, empty keeps the current track
+	CarIds            []string               `protobuf:"bytes,5,rep,name=car_ids,json=carIds,proto3" json:"car_ids,omitempty"`                                      // Empty keeps the current cars
+	Grid              []string               `protobuf:"bytes,6,rep,name=grid,proto3" json:"grid,omitempty"`                                                        // Starting order, empty for the default order
+	Seed              uint64                 `protobuf:"varint,7,opt,name=seed,proto3" json:"seed,omitempty"`                                                       // 0 picks one from the clock
+	SimMode           SimulationMode         `protobuf:"varint,8,opt,name=sim_mode,json=simMode,proto3,enum=car.SimulationMode" json:"sim_mode,omitempty"`          // New lobbies only, a running session keeps its mode
+	Sectors           int32                  `protobuf:"varint,9,opt,name=sectors,proto3" json:"sectors,omitempty"`                                                 // Timing sectors, 0 for the default
+	Checkpoints       int32                  `protobuf:"varint,10,opt,name=checkpoints,proto3" json:"checkpoints,omitempty"`                                        // Lap counting gates, 0 for the default
+	PitStopTime       float32                `protobuf:"fixed32,11,opt,name=pit_stop_time,json=pitStopTime,proto3" json:"pit_stop_time,omitempty"`                  // Seconds of service in the pit box, 0 for the default
+	MandatoryPitStops int32                  `protobuf:"varint,12,opt,name=mandatory_pit_stops,json=mandatoryPitStops,proto3" json:"mandatory_pit_stops,omitempty"` // Races only
+	Fuel              float32                `protobuf:"fixed32,13,opt,name=fuel,proto3" json:"fuel,omitempty"`                                                     // kg at the start, 0 for the default
+	GeneratedTrack    *TrackGenerator        `protobuf:"bytes,14,opt,name=generated_track,json=generatedTrack,proto3" json:"generated_track,omitempty"`             // Race on a generated track instead of a file
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SessionConfig) Reset() {
+	*x = SessionConfig{}
+	mi := &file_car_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionConfig) ProtoMessage() {}
+
+func (x *SessionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionConfig.ProtoReflect.Descriptor instead.
+func (*SessionConfig) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SessionConfig) GetRaceType() RaceType {
+	if x != nil {
+		return x.RaceType
+	}
+	return RaceType_HOTLAP
+}
+
+func (x *SessionConfig) GetLaps() int32 {
+	if x != nil {
+		return x.Laps
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetTime() int32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetTrack() string {
+	if x != nil {
+		return x.Track
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetCarIds() []string {
+	if x != nil {
+		return x.CarIds
+	}
+	return nil
+}
+
+func (x *SessionConfig) GetGrid() []string {
+	if x != nil {
+		return x.Grid
+	}
+	return nil
+}
+
+func (x *SessionConfig) GetSeed() uint64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetSimMode() SimulationMode {
+	if x != nil {
+		return x.SimMode
+	}
+	return SimulationMode_REALTIME
+}
+
+func (x *SessionConfig) GetSectors() int32 {
+	if x != nil {
+		return x.Sectors
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetCheckpoints() int32 {
+	if x != nil {
+		return x.Checkpoints
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetPitStopTime() float32 {
+	if x != nil {
+		return x.PitStopTime
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetMandatoryPitStops() int32 {
+	if x != nil {
+		return x.MandatoryPitStops
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetFuel() float32 {
+	if x != nil {
+		return x.Fuel
+	}
+	return 0
+}
+
+func (x *SessionConfig) GetGeneratedTrack() *TrackGenerator {
+	if x != nil {
+		return x.GeneratedTrack
+	}
+	return nil
+}
+
+// A procedurally generated track. Zero length, corners and width, and unset
+// width_variation and difficulty, take the defaults.
+type TrackGenerator struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Seed           uint64                 `protobuf:"varint,1,opt,name=seed,proto3" json:"seed,omitempty"`      // 0 follows the session seed, so every race gets a new layout
+	Length         float32                `protobuf:"fixed32,2,opt,name=length,proto3" json:"length,omitempty"` // Metres, roughly
+	Corners        int32                  `protobuf:"varint,3,opt,name=corners,proto3" json:"corners,omitempty"`
+	Width          float32                `protobuf:"fixed32,4,opt,name=width,proto3" json:"width,omitempty"`                                               // Metres edge to edge, on average
+	WidthVariation *float32               `protobuf:"fixed32,5,opt,name=width_variation,json=widthVariation,proto3,oneof" json:"width_variation,omitempty"` // Fraction either way of the average width, 0 for constant width
+	Difficulty     *float32               `protobuf:"fixed32,6,opt,name=difficulty,proto3,oneof" json:"difficulty,omitempty"`                               // 0 flowing to 1 tight and twisty
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TrackGenerator) Reset() {
+	*x = TrackGenerator{}
+	mi := &file_car_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackGenerator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackGenerator) ProtoMessage() {}
+
+func (x *TrackGenerator) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackGenerator.ProtoReflect.Descriptor instead.
+func (*TrackGenerator) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TrackGenerator) GetSeed() uint64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *TrackGenerator) GetLength() float32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *TrackGenerator) GetCorners() int32 {
+	if x != nil {
+		return x.Corners
+	}
+	return 0
+}
+
+func (x *TrackGenerator) GetWidth() float32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *TrackGenerator) GetWidthVariation() float32 {
+	if x != nil && x.WidthVariation != nil {
+		return *x.WidthVariation
+	}
+	return 0
+}
+
+func (x *TrackGenerator) GetDifficulty() float32 {
+	if x != nil && x.Difficulty != nil {
+		return *x.Difficulty
+	}
+	return 0
+}
+
+// ---------------------------------------------------
+// Lobbies
+type SessionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Config        *SessionConfig         `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Phase         RacePhase              `protobuf:"varint,3,opt,name=phase,proto3,enum=car.RacePhase" json:"phase,omitempty"`
+	FreeCars      []string               `protobuf:"bytes,4,rep,name=free_cars,json=freeCars,proto3" json:"free_cars,omitempty"` // Cars nobody has checked in to
+	GameTick      int32                  `protobuf:"varint,5,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	mi := &file_car_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SessionInfo) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetConfig() *SessionConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetPhase() RacePhase {
+	if x != nil {
+		return x.Phase
+	}
+	return RacePhase_PHASE_NOTREADY
+}
+
+func (x *SessionInfo) GetFreeCars() []string {
+	if x != nil {
+		return x.FreeCars
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+type SessionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionInfo         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionList) Reset() {
+	*x = SessionList{}
+	mi := &file_car_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionList) ProtoMessage() {}
+
+func (x *SessionList) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionList.ProtoReflect.Descriptor instead.
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SessionList) GetSessions() []*SessionInfo {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type TrackRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Track          string                 `protobuf:"bytes,1,opt,name=track,proto3" json:"track,omitempty"`                                         // Track id or file name in the tracks directory, or "generated"
+	GeneratedTrack *TrackGenerator        `protobuf:"bytes,2,opt,name=generated_track,json=generatedTrack,proto3" json:"generated_track,omitempty"` // Settings for a generated track
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TrackRequest) Reset() {
+	*x = TrackRequest{}
+	mi := &file_car_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackRequest) ProtoMessage() {}
+
+func (x *TrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackRequest.ProtoReflect.Descriptor instead.
+func (*TrackRequest) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TrackRequest) GetTrack() string {
+	if x != nil {
+		return x.Track
+	}
+	return ""
+}
+
+func (x *TrackRequest) GetGeneratedTrack() *TrackGenerator {
+	if x != nil {
+		return x.GeneratedTrack
+	}
+	return nil
+}
+
+type CarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarRequest) Reset() {
+	*x = CarRequest{}
+	mi := &file_car_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarRequest) ProtoMessage() {}
+
+func (x *CarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarRequest.ProtoReflect.Descriptor instead.
+func (*CarRequest) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CarRequest) GetCarId() string {
+	if x != nil {
+		return x.CarId
+	}
+	return ""
+}
+
+type ControlAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	GameTick      int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlAck) Reset() {
+	*x = ControlAck{}
+	mi := &file_car_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InputAck) String() string {
+func (x *ControlAck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InputAck) ProtoMessage() {}
+func (*ControlAck) ProtoMessage() {}
 
-func (x *InputAck) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[8]
+func (x *ControlAck) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -690,61 +3145,67 @@ func (x *InputAck) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InputAck.ProtoReflect.Descriptor instead.
-func (*InputAck) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ControlAck.ProtoReflect.Descriptor instead.
+func (*ControlAck) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *InputAck) GetAccepted() bool {
+func (x *ControlAck) GetAccepted() bool {
 	if x != nil {
 		return x.Accepted
 	}
 	return false
 }
 
-func (x *InputAck) GetReason() string {
+func (x *ControlAck) GetMessage() string {
 	if x != nil {
-		return x.Reason
+		return x.Message
 	}
 	return ""
 }
 
-func (x *InputAck) GetGameLoop() int32 {
+func (x *ControlAck) GetGameTick() int32 {
 	if x != nil {
-		return x.GameLoop
+		return x.GameTick
 	}
 	return 0
 }
 
-// ---------------------------------------------------
-// Dynamic car state during race
-type CarState struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	Status        CarStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=car.CarStatus" json:"status,omitempty"`
-	Position      *Point3D               `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
-	Heading       float32                `protobuf:"fixed32,4,opt,name=heading,proto3" json:"heading,omitempty"`
-	Speed         float32                `protobuf:"fixed32,5,opt,name=speed,proto3" json:"speed,omitempty"`
-	Lap           int32                  `protobuf:"varint,6,opt,name=lap,proto3" json:"lap,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *CarState) Reset() {
-	*x = CarState{}
-	mi := &file_car_proto_msgTypes[9]
+// Everything the server tracks about a car
+type CarDetails struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	State                *CarState              `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Info                 *CarInfo               `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	CheckedIn            bool                   `protobuf:"varint,3,opt,name=checked_in,json=checkedIn,proto3" json:"checked_in,omitempty"`
+	JumpStart            bool                   `protobuf:"varint,4,opt,name=jump_start,json=jumpStart,proto3" json:"jump_start,omitempty"`
+	OffTrack             bool                   `protobuf:"varint,5,opt,name=off_track,json=offTrack,proto3" json:"off_track,omitempty"`
+	TrackLimitViolations int32                  `protobuf:"varint,6,opt,name=track_limit_violations,json=trackLimitViolations,proto3" json:"track_limit_violations,omitempty"`
+	LapValid             bool                   `protobuf:"varint,7,opt,name=lap_valid,json=lapValid,proto3" json:"lap_valid,omitempty"`
+	BestLapTime          float32                `protobuf:"fixed32,8,opt,name=best_lap_time,json=bestLapTime,proto3" json:"best_lap_time,omitempty"`
+	LapTimes             []float32              `protobuf:"fixed32,9,rep,packed,name=lap_times,json=lapTimes,proto3" json:"lap_times,omitempty"`
+	Steering             float32                `protobuf:"fixed32,10,opt,name=steering,proto3" json:"steering,omitempty"` // Latest input
+	Throttle             float32                `protobuf:"fixed32,11,opt,name=throttle,proto3" json:"throttle,omitempty"`
+	Brake                float32                `protobuf:"fixed32,12,opt,name=brake,proto3" json:"brake,omitempty"`
+	InputStats           *InputStats            `protobuf:"bytes,13,opt,name=input_stats,json=inputStats,proto3" json:"input_stats,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CarDetails) Reset() {
+	*x = CarDetails{}
+	mi := &file_car_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CarState) String() string {
+func (x *CarDetails) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CarState) ProtoMessage() {}
+func (*CarDetails) ProtoMessage() {}
 
-func (x *CarState) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[9]
+func (x *CarDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -755,147 +3216,133 @@ func (x *CarState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CarState.ProtoReflect.Descriptor instead.
-func (*CarState) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use CarDetails.ProtoReflect.Descriptor instead.
+func (*CarDetails) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *CarState) GetCarId() string {
+func (x *CarDetails) GetState() *CarState {
 	if x != nil {
-		return x.CarId
+		return x.State
 	}
-	return ""
+	return nil
 }
 
-func (x *CarState) GetStatus() CarStatus {
+func (x *CarDetails) GetInfo() *CarInfo {
 	if x != nil {
-		return x.Status
+		return x.Info
 	}
-	return CarStatus_NOTREADY
+	return nil
 }
 
-func (x *CarState) GetPosition() *Point3D {
+func (x *CarDetails) GetCheckedIn() bool {
 	if x != nil {
-		return x.Position
+		return x.CheckedIn
 	}
-	return nil
+	return false
 }
 
-func (x *CarState) GetHeading() float32 {
+func (x *CarDetails) GetJumpStart() bool {
 	if x != nil {
-		return x.Heading
+		return x.JumpStart
 	}
-	return 0
+	return false
 }
 
-func (x *CarState) GetSpeed() float32 {
+func (x *CarDetails) GetOffTrack() bool {
 	if x != nil {
-		return x.Speed
+		return x.OffTrack
 	}
-	return 0
+	return false
 }
 
-func (x *CarState) GetLap() int32 {
+func (x *CarDetails) GetTrackLimitViolations() int32 {
 	if x != nil {
-		return x.Lap
+		return x.TrackLimitViolations
 	}
 	return 0
 }
 
-type CarPenalty struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	CarId            string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	Reason           string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
-	GameTick         int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
-	RemainingPenalty int32                  `protobuf:"varint,4,opt,name=remaining_penalty,json=remainingPenalty,proto3" json:"remaining_penalty,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
-}
-
-func (x *CarPenalty) Reset() {
-	*x = CarPenalty{}
-	mi := &file_car_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CarPenalty) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CarDetails) GetLapValid() bool {
+	if x != nil {
+		return x.LapValid
+	}
+	return false
 }
 
-func (*CarPenalty) ProtoMessage() {}
-
-func (x *CarPenalty) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[10]
+func (x *CarDetails) GetBestLapTime() float32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.BestLapTime
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use CarPenalty.ProtoReflect.Descriptor instead.
-func (*CarPenalty) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{10}
+func (x *CarDetails) GetLapTimes() []float32 {
+	if x != nil {
+		return x.LapTimes
+	}
+	return nil
 }
 
-func (x *CarPenalty) GetCarId() string {
+func (x *CarDetails) GetSteering() float32 {
 	if x != nil {
-		return x.CarId
+		return x.Steering
 	}
-	return ""
+	return 0
 }
 
-func (x *CarPenalty) GetReason() string {
+func (x *CarDetails) GetThrottle() float32 {
 	if x != nil {
-		return x.Reason
+		return x.Throttle
 	}
-	return ""
+	return 0
 }
 
-func (x *CarPenalty) GetGameTick() int32 {
+func (x *CarDetails) GetBrake() float32 {
 	if x != nil {
-		return x.GameTick
+		return x.Brake
 	}
 	return 0
 }
 
-func (x *CarPenalty) GetRemainingPenalty() int32 {
+func (x *CarDetails) GetInputStats() *InputStats {
 	if x != nil {
-		return x.RemainingPenalty
+		return x.InputStats
 	}
-	return 0
+	return nil
 }
 
-// ---------------------------------------------------
-// Race status information
-type RaceStatus struct {
+// How a car's inputs have been arriving this race
+type InputStats struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "waiting", "racing", "finished"
-	TotalLaps     int32                  `protobuf:"varint,2,opt,name=total_laps,json=totalLaps,proto3" json:"total_laps,omitempty"`
-	GameTick      int32                  `protobuf:"varint,3,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // Of the input in use
+	Received      int32                  `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`
+	Stale         int32                  `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`                                         // Discarded as older than the input in use
+	Late          int32                  `protobuf:"varint,4,opt,name=late,proto3" json:"late,omitempty"`                                           // Taken after the tick they were for had been stepped
+	MeanTicksLate float32                `protobuf:"fixed32,5,opt,name=mean_ticks_late,json=meanTicksLate,proto3" json:"mean_ticks_late,omitempty"` // Over inputs with a target tick, on time counting as 0
+	MaxTicksLate  int32                  `protobuf:"varint,6,opt,name=max_ticks_late,json=maxTicksLate,proto3" json:"max_ticks_late,omitempty"`
+	MeanResponse  float32                `protobuf:"fixed32,7,opt,name=mean_response,json=meanResponse,proto3" json:"mean_response,omitempty"` // Milliseconds from the update an input answers to its arrival
+	MaxResponse   float32                `protobuf:"fixed32,8,opt,name=max_response,json=maxResponse,proto3" json:"max_response,omitempty"`
+	Future        int32                  `protobuf:"varint,9,opt,name=future,proto3" json:"future,omitempty"` // Discarded as for a tick past the next one
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RaceStatus) Reset() {
-	*x = RaceStatus{}
-	mi := &file_car_proto_msgTypes[11]
+func (x *InputStats) Reset() {
+	*x = InputStats{}
+	mi := &file_car_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RaceStatus) String() string {
+func (x *InputStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RaceStatus) ProtoMessage() {}
+func (*InputStats) ProtoMessage() {}
 
-func (x *RaceStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[11]
+func (x *InputStats) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -906,129 +3353,103 @@ func (x *RaceStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RaceStatus.ProtoReflect.Descriptor instead.
-func (*RaceStatus) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use InputStats.ProtoReflect.Descriptor instead.
+func (*InputStats) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *RaceStatus) GetStatus() string {
+func (x *InputStats) GetSequence() uint64 {
 	if x != nil {
-		return x.Status
+		return x.Sequence
 	}
-	return ""
+	return 0
 }
 
-func (x *RaceStatus) GetTotalLaps() int32 {
+func (x *InputStats) GetReceived() int32 {
 	if x != nil {
-		return x.TotalLaps
+		return x.Received
 	}
 	return 0
 }
 
-func (x *RaceStatus) GetGameTick() int32 {
+func (x *InputStats) GetStale() int32 {
 	if x != nil {
-		return x.GameTick
+		return x.Stale
 	}
 	return 0
 }
 
-type CarInterval struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarId         string                 `protobuf:"bytes,1,opt,name=car_id,json=carId,proto3" json:"car_id,omitempty"`
-	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
-	Laps          int32                  `protobuf:"varint,3,opt,name=laps,proto3" json:"laps,omitempty"`
-	Interval      float32                `protobuf:"fixed32,4,opt,name=interval,proto3" json:"interval,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *CarInterval) Reset() {
-	*x = CarInterval{}
-	mi := &file_car_proto_msgTypes[12]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CarInterval) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*CarInterval) ProtoMessage() {}
-
-func (x *CarInterval) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[12]
+func (x *InputStats) GetLate() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Late
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use CarInterval.ProtoReflect.Descriptor instead.
-func (*CarInterval) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{12}
+func (x *InputStats) GetMeanTicksLate() float32 {
+	if x != nil {
+		return x.MeanTicksLate
+	}
+	return 0
 }
 
-func (x *CarInterval) GetCarId() string {
+func (x *InputStats) GetMaxTicksLate() int32 {
 	if x != nil {
-		return x.CarId
+		return x.MaxTicksLate
 	}
-	return ""
+	return 0
 }
 
-func (x *CarInterval) GetPosition() int32 {
+func (x *InputStats) GetMeanResponse() float32 {
 	if x != nil {
-		return x.Position
+		return x.MeanResponse
 	}
 	return 0
 }
 
-func (x *CarInterval) GetLaps() int32 {
+func (x *InputStats) GetMaxResponse() float32 {
 	if x != nil {
-		return x.Laps
+		return x.MaxResponse
 	}
 	return 0
 }
 
-func (x *CarInterval) GetInterval() float32 {
+func (x *InputStats) GetFuture() int32 {
 	if x != nil {
-		return x.Interval
+		return x.Future
 	}
 	return 0
 }
 
-// ---------------------------------------------------
-// Race update (streamed continuously)
-type RaceUpdate struct {
+type RaceControlState struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RaceStatus    *RaceStatus            `protobuf:"bytes,1,opt,name=race_status,json=raceStatus,proto3" json:"race_status,omitempty"`
-	Cars          []*CarState            `protobuf:"bytes,2,rep,name=cars,proto3" json:"cars,omitempty"`
-	Penalties     []*CarPenalty          `protobuf:"bytes,3,rep,name=penalties,proto3" json:"penalties,omitempty"`
-	ToLeader      []*CarInterval         `protobuf:"bytes,4,rep,name=to_leader,json=toLeader,proto3" json:"to_leader,omitempty"`
-	ForPosition   []*CarInterval         `protobuf:"bytes,5,rep,name=for_position,json=forPosition,proto3" json:"for_position,omitempty"`
-	GameTick      int32                  `protobuf:"varint,100,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Session       *SessionConfig         `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Phase         RacePhase              `protobuf:"varint,2,opt,name=phase,proto3,enum=car.RacePhase" json:"phase,omitempty"`
+	Paused        bool                   `protobuf:"varint,3,opt,name=paused,proto3" json:"paused,omitempty"`
+	SimMode       SimulationMode         `protobuf:"varint,4,opt,name=sim_mode,json=simMode,proto3,enum=car.SimulationMode" json:"sim_mode,omitempty"`
+	GameTick      int32                  `protobuf:"varint,5,opt,name=game_tick,json=gameTick,proto3" json:"game_tick,omitempty"`
+	Update        *RaceUpdate            `protobuf:"bytes,6,opt,name=update,proto3" json:"update,omitempty"`
+	Cars          []*CarDetails          `protobuf:"bytes,7,rep,name=cars,proto3" json:"cars,omitempty"`
+	StateHash     uint64                 `protobuf:"varint,8,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RaceUpdate) Reset() {
-	*x = RaceUpdate{}
-	mi := &file_car_proto_msgTypes[13]
+func (x *RaceControlState) Reset() {
+	*x = RaceControlState{}
+	mi := &file_car_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RaceUpdate) String() string {
+func (x *RaceControlState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RaceUpdate) ProtoMessage() {}
+func (*RaceControlState) ProtoMessage() {}
 
-func (x *RaceUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_car_proto_msgTypes[13]
+func (x *RaceControlState) ProtoReflect() protoreflect.Message {
+	mi := &file_car_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1039,49 +3460,63 @@ func (x *RaceUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RaceUpdate.ProtoReflect.Descriptor instead.
-func (*RaceUpdate) Descriptor() ([]byte, []int) {
-	return file_car_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use RaceControlState.ProtoReflect.Descriptor instead.
+func (*RaceControlState) Descriptor() ([]byte, []int) {
+	return file_car_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *RaceUpdate) GetRaceStatus() *RaceStatus {
+func (x *RaceControlState) GetSession() *SessionConfig {
 	if x != nil {
-		return x.RaceStatus
+		return x.Session
 	}
 	return nil
 }
 
-func (x *RaceUpdate) GetCars() []*CarState {
+func (x *RaceControlState) GetPhase() RacePhase {
 	if x != nil {
-		return x.Cars
+		return x.Phase
 	}
-	return nil
+	return RacePhase_PHASE_NOTREADY
 }
 
-func (x *RaceUpdate) GetPenalties() []*CarPenalty {
+func (x *RaceControlState) GetPaused() bool {
 	if x != nil {
-		return x.Penalties
+		return x.Paused
 	}
-	return nil
+	return false
 }
 
-func (x *RaceUpdate) GetToLeader() []*CarInterval {
+func (x *RaceControlState) GetSimMode() SimulationMode {
 	if x != nil {
-		return x.ToLeader
+		return x.SimMode
+	}
+	return SimulationMode_REALTIME
+}
+
+func (x *RaceControlState) GetGameTick() int32 {
+	if x != nil {
+		return x.GameTick
+	}
+	return 0
+}
+
+func (x *RaceControlState) GetUpdate() *RaceUpdate {
+	if x != nil {
+		return x.Update
 	}
 	return nil
 }
 
-func (x *RaceUpdate) GetForPosition() []*CarInterval {
+func (x *RaceControlState) GetCars() []*CarDetails {
 	if x != nil {
-		return x.ForPosition
+		return x.Cars
 	}
 	return nil
 }
 
-func (x *RaceUpdate) GetGameTick() int32 {
+func (x *RaceControlState) GetStateHash() uint64 {
 	if x != nil {
-		return x.GameTick
+		return x.StateHash
 	}
 	return 0
 }
@@ -1091,29 +3526,72 @@ var File_car_proto protoreflect.FileDescriptor
 const file_car_proto_rawDesc = "" +
 	"\n" +
 	"\tcar.proto\x12\x03car\"\a\n" +
-	"\x05Empty\"3\n" +
+	"\x05Empty\"/\n" +
+	"\x0eSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"3\n" +
 	"\aPoint3D\x12\f\n" +
 	"\x01x\x18\x01 \x01(\x02R\x01x\x12\f\n" +
 	"\x01y\x18\x02 \x01(\x02R\x01y\x12\f\n" +
-	"\x01z\x18\x03 \x01(\x02R\x01z\"\xa2\x01\n" +
+	"\x01z\x18\x03 \x01(\x02R\x01z\"\xa7\x02\n" +
 	"\tTrackInfo\x12\x19\n" +
 	"\btrack_id\x18\x01 \x01(\tR\atrackId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
 	"\rleft_boundary\x18\x03 \x03(\v2\f.car.Point3DR\fleftBoundary\x123\n" +
-	"\x0eright_boundary\x18\x04 \x03(\v2\f.car.Point3DR\rrightBoundary\"d\n" +
+	"\x0eright_boundary\x18\x04 \x03(\v2\f.car.Point3DR\rrightBoundary\x12'\n" +
+	"\bpit_lane\x18\x05 \x01(\v2\f.car.PitLaneR\apitLane\x12&\n" +
+	"\x05zones\x18\x06 \x03(\v2\x10.car.SurfaceZoneR\x05zones\x122\n" +
+	"\bsurfaces\x18\a \x03(\v2\x16.car.SurfacePropertiesR\bsurfaces\"\x9d\x01\n" +
+	"\vSurfaceZone\x12&\n" +
+	"\asurface\x18\x01 \x01(\x0e2\f.car.SurfaceR\asurface\x12\x12\n" +
+	"\x04left\x18\x02 \x01(\bR\x04left\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\x05R\x03end\x12\x14\n" +
+	"\x05inner\x18\x05 \x01(\x02R\x05inner\x12\x14\n" +
+	"\x05outer\x18\x06 \x01(\x02R\x05outer\"k\n" +
+	"\x11SurfaceProperties\x12&\n" +
+	"\asurface\x18\x01 \x01(\x0e2\f.car.SurfaceR\asurface\x12\x1a\n" +
+	"\bfriction\x18\x02 \x01(\x02R\bfriction\x12\x12\n" +
+	"\x04drag\x18\x03 \x01(\x02R\x04drag\"\xad\x02\n" +
+	"\fTrackSummary\x12\x19\n" +
+	"\btrack_id\x18\x01 \x01(\tR\atrackId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x12\n" +
+	"\x04file\x18\x04 \x01(\tR\x04file\x12\x16\n" +
+	"\x06length\x18\x05 \x01(\x02R\x06length\x121\n" +
+	"\tdirection\x18\x06 \x01(\x0e2\x13.car.TrackDirectionR\tdirection\x12+\n" +
+	"\n" +
+	"start_left\x18\a \x01(\v2\f.car.Point3DR\tstartLeft\x12-\n" +
+	"\vstart_right\x18\b \x01(\v2\f.car.Point3DR\n" +
+	"startRight\x12\x19\n" +
+	"\bpit_lane\x18\t \x01(\bR\apitLane\"6\n" +
+	"\tTrackList\x12)\n" +
+	"\x06tracks\x18\x01 \x03(\v2\x11.car.TrackSummaryR\x06tracks\"\xd8\x01\n" +
+	"\aPitLane\x121\n" +
+	"\rleft_boundary\x18\x01 \x03(\v2\f.car.Point3DR\fleftBoundary\x123\n" +
+	"\x0eright_boundary\x18\x02 \x03(\v2\f.car.Point3DR\rrightBoundary\x12\x1f\n" +
+	"\vspeed_limit\x18\x03 \x01(\x02R\n" +
+	"speedLimit\x12#\n" +
+	"\rlimiter_start\x18\x04 \x01(\x02R\flimiterStart\x12\x1f\n" +
+	"\vlimiter_end\x18\x05 \x01(\x02R\n" +
+	"limiterEnd\"d\n" +
 	"\x0fRaceDescription\x12)\n" +
 	"\bracetype\x18\x01 \x01(\x0e2\r.car.RaceTypeR\bracetype\x12\x12\n" +
 	"\x04laps\x18d \x01(\x05R\x04laps\x12\x12\n" +
-	"\x04time\x18e \x01(\x05R\x04time\"N\n" +
+	"\x04time\x18e \x01(\x05R\x04time\"\x9e\x01\n" +
 	"\aCarInfo\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12\x14\n" +
 	"\x05power\x18\x03 \x01(\x02R\x05power\x12\x16\n" +
-	"\x06weight\x18\x04 \x01(\x02R\x06weight\"d\n" +
+	"\x06weight\x18\x04 \x01(\x02R\x06weight\x12#\n" +
+	"\rfuel_capacity\x18\x05 \x01(\x02R\ffuelCapacity\x12)\n" +
+	"\x10fuel_consumption\x18\x06 \x01(\x02R\x0ffuelConsumption\"\x83\x01\n" +
 	"\x0eRegisterPlayer\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12\x1f\n" +
 	"\vplayer_name\x18\x02 \x01(\tR\n" +
 	"playerName\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\"\xd2\x01\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\"\xda\x02\n" +
 	"\x0fCheckInResponse\x12\x1a\n" +
 	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x1d\n" +
 	"\n" +
@@ -1121,43 +3599,90 @@ const file_car_proto_rawDesc = "" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12!\n" +
 	"\fis_spectator\x18\x04 \x01(\bR\visSpectator\x12$\n" +
 	"\x05track\x18\x05 \x01(\v2\x0e.car.TrackInfoR\x05track\x12!\n" +
-	"\x04race\x18\x06 \x01(\x0e2\r.car.RaceTypeR\x04race\"\xaf\x01\n" +
+	"\x04race\x18\x06 \x01(\x0e2\r.car.RaceTypeR\x04race\x12 \n" +
+	"\x04cars\x18\a \x03(\v2\f.car.CarInfoR\x04cars\x12.\n" +
+	"\bsim_mode\x18\b \x01(\x0e2\x13.car.SimulationModeR\asimMode\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\t \x01(\tR\tsessionId\x12\x15\n" +
+	"\x06car_id\x18\n" +
+	" \x01(\tR\x05carId\"\xcb\x02\n" +
 	"\vPlayerInput\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12\x1d\n" +
 	"\n" +
 	"auth_token\x18\x02 \x01(\tR\tauthToken\x12\x1a\n" +
 	"\bsteering\x18\x03 \x01(\x02R\bsteering\x12\x1a\n" +
 	"\bthrottle\x18\x04 \x01(\x02R\bthrottle\x12\x14\n" +
-	"\x05brake\x18\x05 \x01(\x02R\x05brake\x12\x1c\n" +
-	"\ttimestamp\x18c \x01(\x05R\ttimestamp\"[\n" +
+	"\x05brake\x18\x05 \x01(\x02R\x05brake\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x06 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"pit_refuel\x18\a \x01(\x02R\tpitRefuel\x12\x1b\n" +
+	"\tpit_tyres\x18\b \x01(\bR\bpitTyres\x12\x1a\n" +
+	"\bsequence\x18\t \x01(\x04R\bsequence\x12\x1f\n" +
+	"\vtarget_tick\x18\n" +
+	" \x01(\x05R\n" +
+	"targetTick\x12 \n" +
+	"\ttimestamp\x18c \x01(\x05B\x02\x18\x01R\ttimestamp\"w\n" +
 	"\bInputAck\x12\x1a\n" +
 	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x16\n" +
 	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1b\n" +
-	"\tgame_loop\x18\x03 \x01(\x05R\bgameLoop\"\xb5\x01\n" +
+	"\tgame_loop\x18\x03 \x01(\x05R\bgameLoop\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xa5\x01\n" +
+	"\vDriveUpdate\x12\x1b\n" +
+	"\tgame_tick\x18\x01 \x01(\x05R\bgameTick\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12\x1f\n" +
+	"\x03car\x18\x03 \x01(\v2\r.car.CarStateR\x03car\x12$\n" +
+	"\x05phase\x18\x04 \x01(\x0e2\x0e.car.RacePhaseR\x05phase\x12\x16\n" +
+	"\x06paused\x18\x05 \x01(\bR\x06paused\"\xa9\x03\n" +
 	"\bCarState\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12&\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x0e.car.CarStatusR\x06status\x12(\n" +
 	"\bposition\x18\x03 \x01(\v2\f.car.Point3DR\bposition\x12\x18\n" +
 	"\aheading\x18\x04 \x01(\x02R\aheading\x12\x14\n" +
 	"\x05speed\x18\x05 \x01(\x02R\x05speed\x12\x10\n" +
-	"\x03lap\x18\x06 \x01(\x05R\x03lap\"\x85\x01\n" +
+	"\x03lap\x18\x06 \x01(\x05R\x03lap\x12\x1b\n" +
+	"\twrong_way\x18\a \x01(\bR\bwrongWay\x12\x1b\n" +
+	"\tpit_stops\x18\b \x01(\x05R\bpitStops\x12\"\n" +
+	"\rpit_stop_left\x18\t \x01(\x02R\vpitStopLeft\x12\x1b\n" +
+	"\ttyre_wear\x18\n" +
+	" \x01(\x02R\btyreWear\x12\x12\n" +
+	"\x04fuel\x18\v \x01(\x02R\x04fuel\x12\x1e\n" +
+	"\n" +
+	"slipstream\x18\f \x01(\x02R\n" +
+	"slipstream\x12\x1b\n" +
+	"\tdirty_air\x18\r \x01(\x02R\bdirtyAir\x12&\n" +
+	"\asurface\x18\x0e \x01(\x0e2\f.car.SurfaceR\asurface\"\xab\x01\n" +
 	"\n" +
 	"CarPenalty\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12\x16\n" +
 	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1b\n" +
 	"\tgame_tick\x18\x03 \x01(\x05R\bgameTick\x12+\n" +
-	"\x11remaining_penalty\x18\x04 \x01(\x05R\x10remainingPenalty\"`\n" +
+	"\x11remaining_penalty\x18\x04 \x01(\x05R\x10remainingPenalty\x12$\n" +
+	"\x04kind\x18\x05 \x01(\x0e2\x10.car.PenaltyKindR\x04kind\"\xb4\x02\n" +
 	"\n" +
 	"RaceStatus\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
 	"total_laps\x18\x02 \x01(\x05R\ttotalLaps\x12\x1b\n" +
-	"\tgame_tick\x18\x03 \x01(\x05R\bgameTick\"p\n" +
+	"\tgame_tick\x18\x03 \x01(\x05R\bgameTick\x12*\n" +
+	"\trace_type\x18\x04 \x01(\x0e2\r.car.RaceTypeR\braceType\x12\x1b\n" +
+	"\ttime_left\x18\x05 \x01(\x05R\btimeLeft\x12$\n" +
+	"\x05phase\x18\x06 \x01(\x0e2\x0e.car.RacePhaseR\x05phase\x12\x1b\n" +
+	"\tlights_on\x18\a \x01(\x05R\blightsOn\x12\x16\n" +
+	"\x06paused\x18\b \x01(\bR\x06paused\x12.\n" +
+	"\x13mandatory_pit_stops\x18\t \x01(\x05R\x11mandatoryPitStops\"\xb4\x01\n" +
+	"\fContactEvent\x12\x13\n" +
+	"\x05car_a\x18\x01 \x01(\tR\x04carA\x12\x13\n" +
+	"\x05car_b\x18\x02 \x01(\tR\x04carB\x12\x1b\n" +
+	"\tgame_tick\x18\x03 \x01(\x05R\bgameTick\x12\x18\n" +
+	"\aimpulse\x18\x04 \x01(\x02R\aimpulse\x12(\n" +
+	"\bposition\x18\x05 \x01(\v2\f.car.Point3DR\bposition\x12\x19\n" +
+	"\bat_fault\x18\x06 \x01(\tR\aatFault\"p\n" +
 	"\vCarInterval\x12\x15\n" +
 	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12\x1a\n" +
 	"\bposition\x18\x02 \x01(\x05R\bposition\x12\x12\n" +
 	"\x04laps\x18\x03 \x01(\x05R\x04laps\x12\x1a\n" +
-	"\binterval\x18\x04 \x01(\x02R\binterval\"\x91\x02\n" +
+	"\binterval\x18\x04 \x01(\x02R\binterval\"\xe9\x02\n" +
 	"\n" +
 	"RaceUpdate\x120\n" +
 	"\vrace_status\x18\x01 \x01(\v2\x0f.car.RaceStatusR\n" +
@@ -1165,8 +3690,153 @@ const file_car_proto_rawDesc = "" +
 	"\x04cars\x18\x02 \x03(\v2\r.car.CarStateR\x04cars\x12-\n" +
 	"\tpenalties\x18\x03 \x03(\v2\x0f.car.CarPenaltyR\tpenalties\x12-\n" +
 	"\tto_leader\x18\x04 \x03(\v2\x10.car.CarIntervalR\btoLeader\x123\n" +
-	"\ffor_position\x18\x05 \x03(\v2\x10.car.CarIntervalR\vforPosition\x12\x1b\n" +
-	"\tgame_tick\x18d \x01(\x05R\bgameTick*A\n" +
+	"\ffor_position\x18\x05 \x03(\v2\x10.car.CarIntervalR\vforPosition\x12-\n" +
+	"\bcontacts\x18\x06 \x03(\v2\x11.car.ContactEventR\bcontacts\x12'\n" +
+	"\x06timing\x18\a \x01(\v2\x0f.car.TimingInfoR\x06timing\x12\x1b\n" +
+	"\tgame_tick\x18d \x01(\x05R\bgameTick\"\xef\x03\n" +
+	"\tCarTiming\x12\x15\n" +
+	"\x06car_id\x18\x01 \x01(\tR\x05carId\x12%\n" +
+	"\x0ecurrent_sector\x18\x02 \x01(\x05R\rcurrentSector\x12(\n" +
+	"\x10current_lap_time\x18\x03 \x01(\x02R\x0ecurrentLapTime\x12\"\n" +
+	"\rlast_lap_time\x18\x04 \x01(\x02R\vlastLapTime\x12\"\n" +
+	"\rbest_lap_time\x18\x05 \x01(\x02R\vbestLapTime\x12\x1b\n" +
+	"\tlap_times\x18\x06 \x03(\x02R\blapTimes\x12!\n" +
+	"\fsector_times\x18\a \x03(\x02R\vsectorTimes\x12*\n" +
+	"\x11last_sector_times\x18\b \x03(\x02R\x0flastSectorTimes\x12*\n" +
+	"\x11best_sector_times\x18\t \x03(\x02R\x0fbestSectorTimes\x12\x1b\n" +
+	"\tlap_valid\x18\n" +
+	" \x01(\bR\blapValid\x12%\n" +
+	"\x0fgap_to_best_lap\x18\v \x01(\x02R\fgapToBestLap\x12'\n" +
+	"\x0fnext_checkpoint\x18\f \x01(\x05R\x0enextCheckpoint\x12-\n" +
+	"\x12missed_checkpoints\x18\r \x01(\x05R\x11missedCheckpoints\"\xb3\x02\n" +
+	"\n" +
+	"TimingInfo\x12\x18\n" +
+	"\asectors\x18\x01 \x01(\x05R\asectors\x12\x1f\n" +
+	"\vsector_ends\x18\x02 \x03(\x02R\n" +
+	"sectorEnds\x12\"\n" +
+	"\x04cars\x18\x03 \x03(\v2\x0e.car.CarTimingR\x04cars\x12\"\n" +
+	"\rbest_lap_time\x18\x04 \x01(\x02R\vbestLapTime\x12%\n" +
+	"\x0fbest_lap_car_id\x18\x05 \x01(\tR\fbestLapCarId\x12*\n" +
+	"\x11best_sector_times\x18\x06 \x03(\x02R\x0fbestSectorTimes\x12-\n" +
+	"\x13best_sector_car_ids\x18\a \x03(\tR\x10bestSectorCarIds\x12 \n" +
+	"\vcheckpoints\x18\b \x03(\x02R\vcheckpoints\"\xd7\x02\n" +
+	"\vResultEntry\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x12\x15\n" +
+	"\x06car_id\x18\x02 \x01(\tR\x05carId\x12&\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x0e.car.CarStatusR\x06status\x12\x12\n" +
+	"\x04laps\x18\x04 \x01(\x05R\x04laps\x12\x1d\n" +
+	"\n" +
+	"total_time\x18\x05 \x01(\x02R\ttotalTime\x12\"\n" +
+	"\rbest_lap_time\x18\x06 \x01(\x02R\vbestLapTime\x12\x10\n" +
+	"\x03gap\x18\a \x01(\x02R\x03gap\x12\x1f\n" +
+	"\vlaps_behind\x18\b \x01(\x05R\n" +
+	"lapsBehind\x12\x1c\n" +
+	"\tpenalties\x18\t \x01(\x05R\tpenalties\x12!\n" +
+	"\fpenalty_time\x18\n" +
+	" \x01(\x02R\vpenaltyTime\x12\"\n" +
+	"\fdisqualified\x18\v \x01(\tR\fdisqualified\"\xc1\x01\n" +
+	"\aResults\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12*\n" +
+	"\trace_type\x18\x02 \x01(\x0e2\r.car.RaceTypeR\braceType\x12\x14\n" +
+	"\x05final\x18\x03 \x01(\bR\x05final\x12\x1b\n" +
+	"\tgame_tick\x18\x04 \x01(\x05R\bgameTick\x128\n" +
+	"\x0eclassification\x18\x05 \x03(\v2\x10.car.ResultEntryR\x0eclassification\"\xcc\x03\n" +
+	"\rSessionConfig\x12*\n" +
+	"\trace_type\x18\x01 \x01(\x0e2\r.car.RaceTypeR\braceType\x12\x12\n" +
+	"\x04laps\x18\x02 \x01(\x05R\x04laps\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\x05R\x04time\x12\x14\n" +
+	"\x05track\x18\x04 \x01(\tR\x05track\x12\x17\n" +
+	"\acar_ids\x18\x05 \x03(\tR\x06carIds\x12\x12\n" +
+	"\x04grid\x18\x06 \x03(\tR\x04grid\x12\x12\n" +
+	"\x04seed\x18\a \x01(\x04R\x04seed\x12.\n" +
+	"\bsim_mode\x18\b \x01(\x0e2\x13.car.SimulationModeR\asimMode\x12\x18\n" +
+	"\asectors\x18\t \x01(\x05R\asectors\x12 \n" +
+	"\vcheckpoints\x18\n" +
+	" \x01(\x05R\vcheckpoints\x12\"\n" +
+	"\rpit_stop_time\x18\v \x01(\x02R\vpitStopTime\x12.\n" +
+	"\x13mandatory_pit_stops\x18\f \x01(\x05R\x11mandatoryPitStops\x12\x12\n" +
+	"\x04fuel\x18\r \x01(\x02R\x04fuel\x12<\n" +
+	"\x0fgenerated_track\x18\x0e \x01(\v2\x13.car.TrackGeneratorR\x0egeneratedTrack\"\xe2\x01\n" +
+	"\x0eTrackGenerator\x12\x12\n" +
+	"\x04seed\x18\x01 \x01(\x04R\x04seed\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x02R\x06length\x12\x18\n" +
+	"\acorners\x18\x03 \x01(\x05R\acorners\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x02R\x05width\x12,\n" +
+	"\x0fwidth_variation\x18\x05 \x01(\x02H\x00R\x0ewidthVariation\x88\x01\x01\x12#\n" +
+	"\n" +
+	"difficulty\x18\x06 \x01(\x02H\x01R\n" +
+	"difficulty\x88\x01\x01B\x12\n" +
+	"\x10_width_variationB\r\n" +
+	"\v_difficulty\"\xb8\x01\n" +
+	"\vSessionInfo\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12*\n" +
+	"\x06config\x18\x02 \x01(\v2\x12.car.SessionConfigR\x06config\x12$\n" +
+	"\x05phase\x18\x03 \x01(\x0e2\x0e.car.RacePhaseR\x05phase\x12\x1b\n" +
+	"\tfree_cars\x18\x04 \x03(\tR\bfreeCars\x12\x1b\n" +
+	"\tgame_tick\x18\x05 \x01(\x05R\bgameTick\";\n" +
+	"\vSessionList\x12,\n" +
+	"\bsessions\x18\x01 \x03(\v2\x10.car.SessionInfoR\bsessions\"b\n" +
+	"\fTrackRequest\x12\x14\n" +
+	"\x05track\x18\x01 \x01(\tR\x05track\x12<\n" +
+	"\x0fgenerated_track\x18\x02 \x01(\v2\x13.car.TrackGeneratorR\x0egeneratedTrack\"#\n" +
+	"\n" +
+	"CarRequest\x12\x15\n" +
+	"\x06car_id\x18\x01 \x01(\tR\x05carId\"_\n" +
+	"\n" +
+	"ControlAck\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
+	"\tgame_tick\x18\x03 \x01(\x05R\bgameTick\"\xc2\x03\n" +
+	"\n" +
+	"CarDetails\x12#\n" +
+	"\x05state\x18\x01 \x01(\v2\r.car.CarStateR\x05state\x12 \n" +
+	"\x04info\x18\x02 \x01(\v2\f.car.CarInfoR\x04info\x12\x1d\n" +
+	"\n" +
+	"checked_in\x18\x03 \x01(\bR\tcheckedIn\x12\x1d\n" +
+	"\n" +
+	"jump_start\x18\x04 \x01(\bR\tjumpStart\x12\x1b\n" +
+	"\toff_track\x18\x05 \x01(\bR\boffTrack\x124\n" +
+	"\x16track_limit_violations\x18\x06 \x01(\x05R\x14trackLimitViolations\x12\x1b\n" +
+	"\tlap_valid\x18\a \x01(\bR\blapValid\x12\"\n" +
+	"\rbest_lap_time\x18\b \x01(\x02R\vbestLapTime\x12\x1b\n" +
+	"\tlap_times\x18\t \x03(\x02R\blapTimes\x12\x1a\n" +
+	"\bsteering\x18\n" +
+	" \x01(\x02R\bsteering\x12\x1a\n" +
+	"\bthrottle\x18\v \x01(\x02R\bthrottle\x12\x14\n" +
+	"\x05brake\x18\f \x01(\x02R\x05brake\x120\n" +
+	"\vinput_stats\x18\r \x01(\v2\x0f.car.InputStatsR\n" +
+	"inputStats\"\x9c\x02\n" +
+	"\n" +
+	"InputStats\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1a\n" +
+	"\breceived\x18\x02 \x01(\x05R\breceived\x12\x14\n" +
+	"\x05stale\x18\x03 \x01(\x05R\x05stale\x12\x12\n" +
+	"\x04late\x18\x04 \x01(\x05R\x04late\x12&\n" +
+	"\x0fmean_ticks_late\x18\x05 \x01(\x02R\rmeanTicksLate\x12$\n" +
+	"\x0emax_ticks_late\x18\x06 \x01(\x05R\fmaxTicksLate\x12#\n" +
+	"\rmean_response\x18\a \x01(\x02R\fmeanResponse\x12!\n" +
+	"\fmax_response\x18\b \x01(\x02R\vmaxResponse\x12\x16\n" +
+	"\x06future\x18\t \x01(\x05R\x06future\"\xb8\x02\n" +
+	"\x10RaceControlState\x12,\n" +
+	"\asession\x18\x01 \x01(\v2\x12.car.SessionConfigR\asession\x12$\n" +
+	"\x05phase\x18\x02 \x01(\x0e2\x0e.car.RacePhaseR\x05phase\x12\x16\n" +
+	"\x06paused\x18\x03 \x01(\bR\x06paused\x12.\n" +
+	"\bsim_mode\x18\x04 \x01(\x0e2\x13.car.SimulationModeR\asimMode\x12\x1b\n" +
+	"\tgame_tick\x18\x05 \x01(\x05R\bgameTick\x12'\n" +
+	"\x06update\x18\x06 \x01(\v2\x0f.car.RaceUpdateR\x06update\x12#\n" +
+	"\x04cars\x18\a \x03(\v2\x0f.car.CarDetailsR\x04cars\x12\x1d\n" +
+	"\n" +
+	"state_hash\x18\b \x01(\x04R\tstateHash*W\n" +
+	"\aSurface\x12\x13\n" +
+	"\x0fSURFACE_ASPHALT\x10\x00\x12\x10\n" +
+	"\fSURFACE_KERB\x10\x01\x12\x11\n" +
+	"\rSURFACE_GRASS\x10\x02\x12\x12\n" +
+	"\x0eSURFACE_GRAVEL\x10\x03*I\n" +
+	"\x0eTrackDirection\x12\x17\n" +
+	"\x13DIRECTION_CLOCKWISE\x10\x00\x12\x1e\n" +
+	"\x1aDIRECTION_COUNTERCLOCKWISE\x10\x01*A\n" +
 	"\bRaceType\x12\n" +
 	"\n" +
 	"\x06HOTLAP\x10\x00\x12\t\n" +
@@ -1174,22 +3844,71 @@ const file_car_proto_rawDesc = "" +
 	"\n" +
 	"RACEBYLAPS\x10\x02\x12\x0e\n" +
 	"\n" +
-	"RACEBYTIME\x10\x03*T\n" +
+	"RACEBYTIME\x10\x03*6\n" +
+	"\x0eSimulationMode\x12\f\n" +
+	"\bREALTIME\x10\x00\x12\b\n" +
+	"\x04FAST\x10\x01\x12\f\n" +
+	"\bLOCKSTEP\x10\x02*`\n" +
 	"\tCarStatus\x12\f\n" +
 	"\bNOTREADY\x10\x00\x12\v\n" +
 	"\aWAITING\x10\x01\x12\n" +
 	"\n" +
 	"\x06RACING\x10\x02\x12\x12\n" +
-	"\x0eSERVINGPENALTY\x10\x03\x12\f\n" +
-	"\bFINISHED\x10c2\xd2\x01\n" +
+	"\x0eSERVINGPENALTY\x10\x03\x12\n" +
+	"\n" +
+	"\x06INPITS\x10\x04\x12\f\n" +
+	"\bFINISHED\x10c*O\n" +
+	"\vPenaltyKind\x12\x10\n" +
+	"\fPENALTY_TIME\x10\x00\x12\x19\n" +
+	"\x15PENALTY_DRIVE_THROUGH\x10\x01\x12\x13\n" +
+	"\x0fPENALTY_STOP_GO\x10\x02*\xa8\x01\n" +
+	"\tRacePhase\x12\x12\n" +
+	"\x0ePHASE_NOTREADY\x10\x00\x12\x11\n" +
+	"\rPHASE_WAITING\x10\x01\x12\x0e\n" +
+	"\n" +
+	"PHASE_GRID\x10\x02\x12\x16\n" +
+	"\x12PHASE_START_LIGHTS\x10\x03\x12\x10\n" +
+	"\fPHASE_RACING\x10\x04\x12\x13\n" +
+	"\x0fPHASE_CHEQUERED\x10\x05\x12\x12\n" +
+	"\x0ePHASE_COOLDOWN\x10\x06\x12\x11\n" +
+	"\rPHASE_RESULTS\x10\a2\x8f\x04\n" +
 	"\n" +
 	"CarService\x124\n" +
-	"\aCheckIn\x12\x13.car.RegisterPlayer\x1a\x14.car.CheckInResponse\x12&\n" +
-	"\bGetTrack\x12\n" +
-	".car.Empty\x1a\x0e.car.TrackInfo\x122\n" +
-	"\x11StreamRaceUpdates\x12\n" +
-	".car.Empty\x1a\x0f.car.RaceUpdate0\x01\x122\n" +
-	"\x0fSendPlayerInput\x12\x10.car.PlayerInput\x1a\r.car.InputAckB\tZ\a./protob\x06proto3"
+	"\aCheckIn\x12\x13.car.RegisterPlayer\x1a\x14.car.CheckInResponse\x12/\n" +
+	"\bGetTrack\x12\x13.car.SessionRequest\x1a\x0e.car.TrackInfo\x12;\n" +
+	"\x11StreamRaceUpdates\x12\x13.car.SessionRequest\x1a\x0f.car.RaceUpdate0\x01\x122\n" +
+	"\x0fSendPlayerInput\x12\x10.car.PlayerInput\x1a\r.car.InputAck\x12/\n" +
+	"\x05Drive\x12\x10.car.PlayerInput\x1a\x10.car.DriveUpdate(\x010\x01\x12,\n" +
+	"\fListSessions\x12\n" +
+	".car.Empty\x1a\x10.car.SessionList\x125\n" +
+	"\rCreateSession\x12\x12.car.SessionConfig\x1a\x10.car.SessionInfo\x128\n" +
+	"\vJoinSession\x12\x13.car.RegisterPlayer\x1a\x14.car.CheckInResponse\x12/\n" +
+	"\n" +
+	"GetResults\x12\x13.car.SessionRequest\x1a\f.car.Results\x12(\n" +
+	"\n" +
+	"ListTracks\x12\n" +
+	".car.Empty\x1a\x0e.car.TrackList2\x82\x05\n" +
+	"\x12RaceControlService\x124\n" +
+	"\rCreateSession\x12\x12.car.SessionConfig\x1a\x0f.car.ControlAck\x12+\n" +
+	"\fStartSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAck\x12+\n" +
+	"\fPauseSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAck\x12,\n" +
+	"\rResumeSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAck\x12+\n" +
+	"\fAbortSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAck\x12-\n" +
+	"\x0eRestartSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAck\x121\n" +
+	"\vChangeTrack\x12\x11.car.TrackRequest\x1a\x0f.car.ControlAck\x12*\n" +
+	"\x06AddCar\x12\x0f.car.CarRequest\x1a\x0f.car.ControlAck\x12-\n" +
+	"\tRemoveCar\x12\x0f.car.CarRequest\x1a\x0f.car.ControlAck\x120\n" +
+	"\fIssuePenalty\x12\x0f.car.CarPenalty\x1a\x0f.car.ControlAck\x122\n" +
+	"\x0eRescindPenalty\x12\x0f.car.CarRequest\x1a\x0f.car.ControlAck\x121\n" +
+	"\fGetRaceState\x12\n" +
+	".car.Empty\x1a\x15.car.RaceControlState\x12+\n" +
+	"\fCloseSession\x12\n" +
+	".car.Empty\x1a\x0f.car.ControlAckB\tZ\a./protob\x06proto3"
 
 var (
 	file_car_proto_rawDescOnce sync.Once
@@ -1203,52 +3922,158 @@ func file_car_proto_rawDescGZIP() []byte {
 	return file_car_proto_rawDescData
 }
 
-var file_car_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_car_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_car_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_car_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_car_proto_goTypes = []any{
-	(RaceType)(0),           // 0: car.RaceType
-	(CarStatus)(0),          // 1: car.CarStatus
-	(*Empty)(nil),           // 2: car.Empty
-	(*Point3D)(nil),         // 3: car.Point3D
-	(*TrackInfo)(nil),       // 4: car.TrackInfo
-	(*RaceDescription)(nil), // 5: car.RaceDescription
-	(*CarInfo)(nil),         // 6: car.CarInfo
-	(*RegisterPlayer)(nil),  // 7: car.RegisterPlayer
-	(*CheckInResponse)(nil), // 8: car.CheckInResponse
-	(*PlayerInput)(nil),     // 9: car.PlayerInput
-	(*InputAck)(nil),        // 10: car.InputAck
-	(*CarState)(nil),        // 11: car.CarState
-	(*CarPenalty)(nil),      // 12: car.CarPenalty
-	(*RaceStatus)(nil),      // 13: car.RaceStatus
-	(*CarInterval)(nil),     // 14: car.CarInterval
-	(*RaceUpdate)(nil),      // 15: car.RaceUpdate
+	(Surface)(0),              // 0: car.Surface
+	(TrackDirection)(0),       // 1: car.TrackDirection
+	(RaceType)(0),             // 2: car.RaceType
+	(SimulationMode)(0),       // 3: car.SimulationMode
+	(CarStatus)(0),            // 4: car.CarStatus
+	(PenaltyKind)(0),          // 5: car.PenaltyKind
+	(RacePhase)(0),            // 6: car.RacePhase
+	(*Empty)(nil),             // 7: car.Empty
+	(*SessionRequest)(nil),    // 8: car.SessionRequest
+	(*Point3D)(nil),           // 9: car.Point3D
+	(*TrackInfo)(nil),         // 10: car.TrackInfo
+	(*SurfaceZone)(nil),       // 11: car.SurfaceZone
+	(*SurfaceProperties)(nil), // 12: car.SurfaceProperties
+	(*TrackSummary)(nil),      // 13: car.TrackSummary
+	(*TrackList)(nil),         // 14: car.TrackList
+	(*PitLane)(nil),           // 15: car.PitLane
+	(*RaceDescription)(nil),   // 16: car.RaceDescription
+	(*CarInfo)(nil),           // 17: car.CarInfo
+	(*RegisterPlayer)(nil),    // 18: car.RegisterPlayer
+	(*CheckInResponse)(nil),   // 19: car.CheckInResponse
+	(*PlayerInput)(nil),       // 20: car.PlayerInput
+	(*InputAck)(nil),          // 21: car.InputAck
+	(*DriveUpdate)(nil),       // 22: car.DriveUpdate
+	(*CarState)(nil),          // 23: car.CarState
+	(*CarPenalty)(nil),        // 24: car.CarPenalty
+	(*RaceStatus)(nil),        // 25: car.RaceStatus
+	(*ContactEvent)(nil),      // 26: car.ContactEvent
+	(*CarInterval)(nil),       // 27: car.CarInterval
+	(*RaceUpdate)(nil),        // 28: car.RaceUpdate
+	(*CarTiming)(nil),         // 29: car.CarTiming
+	(*TimingInfo)(nil),        // 30: car.TimingInfo
+	(*ResultEntry)(nil),       // 31: car.ResultEntry
+	(*Results)(nil),           // 32: car.Results
+	(*SessionConfig)(nil),     // 33: car.SessionConfig
+	(*TrackGenerator)(nil),    // 34: car.TrackGenerator
+	(*SessionInfo)(nil),       // 35: car.SessionInfo
+	(*SessionList)(nil),       // 36: car.SessionList
+	(*TrackRequest)(nil),      // 37: car.TrackRequest
+	(*CarRequest)(nil),        // 38: car.CarRequest
+	(*ControlAck)(nil),        // 39: car.ControlAck
+	(*CarDetails)(nil),        // 40: car.CarDetails
+	(*InputStats)(nil),        // 41: car.InputStats
+	(*RaceControlState)(nil),  // 42: car.RaceControlState
 }
 var file_car_proto_depIdxs = []int32{
-	3,  // 0: car.TrackInfo.left_boundary:type_name -> car.Point3D
-	3,  // 1: car.TrackInfo.right_boundary:type_name -> car.Point3D
-	0,  // 2: car.RaceDescription.racetype:type_name -> car.RaceType
-	4,  // 3: car.CheckInResponse.track:type_name -> car.TrackInfo
-	0,  // 4: car.CheckInResponse.race:type_name -> car.RaceType
-	1,  // 5: car.CarState.status:type_name -> car.CarStatus
-	3,  // 6: car.CarState.position:type_name -> car.Point3D
-	13, // 7: car.RaceUpdate.race_status:type_name -> car.RaceStatus
-	11, // 8: car.RaceUpdate.cars:type_name -> car.CarState
-	12, // 9: car.RaceUpdate.penalties:type_name -> car.CarPenalty
-	14, // 10: car.RaceUpdate.to_leader:type_name -> car.CarInterval
-	14, // 11: car.RaceUpdate.for_position:type_name -> car.CarInterval
-	7,  // 12: car.CarService.CheckIn:input_type -> car.RegisterPlayer
-	2,  // 13: car.CarService.GetTrack:input_type -> car.Empty
-	2,  // 14: car.CarService.StreamRaceUpdates:input_type -> car.Empty
-	9,  // 15: car.CarService.SendPlayerInput:input_type -> car.PlayerInput
-	8,  // 16: car.CarService.CheckIn:output_type -> car.CheckInResponse
-	4,  // 17: car.CarService.GetTrack:output_type -> car.TrackInfo
-	15, // 18: car.CarService.StreamRaceUpdates:output_type -> car.RaceUpdate
-	10, // 19: car.CarService.SendPlayerInput:output_type -> car.InputAck
-	16, // [16:20] is the sub-list for method output_type
-	12, // [12:16] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	9,  // 0: car.TrackInfo.left_boundary:type_name -> car.Point3D
+	9,  // 1: car.TrackInfo.right_boundary:type_name -> car.Point3D
+	15, // 2: car.TrackInfo.pit_lane:type_name -> car.PitLane
+	11, // 3: car.TrackInfo.zones:type_name -> car.SurfaceZone
+	12, // 4: car.TrackInfo.surfaces:type_name -> car.SurfaceProperties
+	0,  // 5: car.SurfaceZone.surface:type_name -> car.Surface
+	0,  // 6: car.SurfaceProperties.surface:type_name -> car.Surface
+	1,  // 7: car.TrackSummary.direction:type_name -> car.TrackDirection
+	9,  // 8: car.TrackSummary.start_left:type_name -> car.Point3D
+	9,  // 9: car.TrackSummary.start_right:type_name -> car.Point3D
+	13, // 10: car.TrackList.tracks:type_name -> car.TrackSummary
+	9,  // 11: car.PitLane.left_boundary:type_name -> car.Point3D
+	9,  // 12: car.PitLane.right_boundary:type_name -> car.Point3D
+	2,  // 13: car.RaceDescription.racetype:type_name -> car.RaceType
+	10, // 14: car.CheckInResponse.track:type_name -> car.TrackInfo
+	2,  // 15: car.CheckInResponse.race:type_name -> car.RaceType
+	17, // 16: car.CheckInResponse.cars:type_name -> car.CarInfo
+	3,  // 17: car.CheckInResponse.sim_mode:type_name -> car.SimulationMode
+	23, // 18: car.DriveUpdate.car:type_name -> car.CarState
+	6,  // 19: car.DriveUpdate.phase:type_name -> car.RacePhase
+	4,  // 20: car.CarState.status:type_name -> car.CarStatus
+	9,  // 21: car.CarState.position:type_name -> car.Point3D
+	0,  // 22: car.CarState.surface:type_name -> car.Surface
+	5,  // 23: car.CarPenalty.kind:type_name -> car.PenaltyKind
+	2,  // 24: car.RaceStatus.race_type:type_name -> car.RaceType
+	6,  // 25: car.RaceStatus.phase:type_name -> car.RacePhase
+	9,  // 26: car.ContactEvent.position:type_name -> car.Point3D
+	25, // 27: car.RaceUpdate.race_status:type_name -> car.RaceStatus
+	23, // 28: car.RaceUpdate.cars:type_name -> car.CarState
+	24, // 29: car.RaceUpdate.penalties:type_name -> car.CarPenalty
+	27, // 30: car.RaceUpdate.to_leader:type_name -> car.CarInterval
+	27, // 31: car.RaceUpdate.for_position:type_name -> car.CarInterval
+	26, // 32: car.RaceUpdate.contacts:type_name -> car.ContactEvent
+	30, // 33: car.RaceUpdate.timing:type_name -> car.TimingInfo
+	29, // 34: car.TimingInfo.cars:type_name -> car.CarTiming
+	4,  // 35: car.ResultEntry.status:type_name -> car.CarStatus
+	2,  // 36: car.Results.race_type:type_name -> car.RaceType
+	31, // 37: car.Results.classification:type_name -> car.ResultEntry
+	2,  // 38: car.SessionConfig.race_type:type_name -> car.RaceType
+	3,  // 39: car.SessionConfig.sim_mode:type_name -> car.SimulationMode
+	34, // 40: car.SessionConfig.generated_track:type_name -> car.TrackGenerator
+	33, // 41: car.SessionInfo.config:type_name -> car.SessionConfig
+	6,  // 42: car.SessionInfo.phase:type_name -> car.RacePhase
+	35, // 43: car.SessionList.sessions:type_name -> car.SessionInfo
+	34, // 44: car.TrackRequest.generated_track:type_name -> car.TrackGenerator
+	23, // 45: car.CarDetails.state:type_name -> car.CarState
+	17, // 46: car.CarDetails.info:type_name -> car.CarInfo
+	41, // 47: car.CarDetails.input_stats:type_name -> car.InputStats
+	33, // 48: car.RaceControlState.session:type_name -> car.SessionConfig
+	6,  // 49: car.RaceControlState.phase:type_name -> car.RacePhase
+	3,  // 50: car.RaceControlState.sim_mode:type_name -> car.SimulationMode
+	28, // 51: car.RaceControlState.update:type_name -> car.RaceUpdate
+	40, // 52: car.RaceControlState.cars:type_name -> car.CarDetails
+	18, // 53: car.CarService.CheckIn:input_type -> car.RegisterPlayer
+	8,  // 54: car.CarService.GetTrack:input_type -> car.SessionRequest
+	8,  // 55: car.CarService.StreamRaceUpdates:input_type -> car.SessionRequest
+	20, // 56: car.CarService.SendPlayerInput:input_type -> car.PlayerInput
+	20, // 57: car.CarService.Drive:input_type -> car.PlayerInput
+	7,  // 58: car.CarService.ListSessions:input_type -> car.Empty
+	33, // 59: car.CarService.CreateSession:input_type -> car.SessionConfig
+	18, // 60: car.CarService.JoinSession:input_type -> car.RegisterPlayer
+	8,  // 61: car.CarService.GetResults:input_type -> car.SessionRequest
+	7,  // 62: car.CarService.ListTracks:input_type -> car.Empty
+	33, // 63: car.RaceControlService.CreateSession:input_type -> car.SessionConfig
+	7,  // 64: car.RaceControlService.StartSession:input_type -> car.Empty
+	7,  // 65: car.RaceControlService.PauseSession:input_type -> car.Empty
+	7,  // 66: car.RaceControlService.ResumeSession:input_type -> car.Empty
+	7,  // 67: car.RaceControlService.AbortSession:input_type -> car.Empty
+	7,  // 68: car.RaceControlService.RestartSession:input_type -> car.Empty
+	37, // 69: car.RaceControlService.ChangeTrack:input_type -> car.TrackRequest
+	38, // 70: car.RaceControlService.AddCar:input_type -> car.CarRequest
+	38, // 71: car.RaceControlService.RemoveCar:input_type -> car.CarRequest
+	24, // 72: car.RaceControlService.IssuePenalty:input_type -> car.CarPenalty
+	38, // 73: car.RaceControlService.RescindPenalty:input_type -> car.CarRequest
+	7,  // 74: car.RaceControlService.GetRaceState:input_type -> car.Empty
+	7,  // 75: car.RaceControlService.CloseSession:input_type -> car.Empty
+	19, // 76: car.CarService.CheckIn:output_type -> car.CheckInResponse
+	10, // 77: car.CarService.GetTrack:output_type -> car.TrackInfo
+	28, // 78: car.CarService.StreamRaceUpdates:output_type -> car.RaceUpdate
+	21, // 79: car.CarService.SendPlayerInput:output_type -> car.InputAck
+	22, // 80: car.CarService.Drive:output_type -> car.DriveUpdate
+	36, // 81: car.CarService.ListSessions:output_type -> car.SessionList
+	35, // 82: car.CarService.CreateSession:output_type -> car.SessionInfo
+	19, // 83: car.CarService.JoinSession:output_type -> car.CheckInResponse
+	32, // 84: car.CarService.GetResults:output_type -> car.Results
+	14, // 85: car.CarService.ListTracks:output_type -> car.TrackList
+	39, // 86: car.RaceControlService.CreateSession:output_type -> car.ControlAck
+	39, // 87: car.RaceControlService.StartSession:output_type -> car.ControlAck
+	39, // 88: car.RaceControlService.PauseSession:output_type -> car.ControlAck
+	39, // 89: car.RaceControlService.ResumeSession:output_type -> car.ControlAck
+	39, // 90: car.RaceControlService.AbortSession:output_type -> car.ControlAck
+	39, // 91: car.RaceControlService.RestartSession:output_type -> car.ControlAck
+	39, // 92: car.RaceControlService.ChangeTrack:output_type -> car.ControlAck
+	39, // 93: car.RaceControlService.AddCar:output_type -> car.ControlAck
+	39, // 94: car.RaceControlService.RemoveCar:output_type -> car.ControlAck
+	39, // 95: car.RaceControlService.IssuePenalty:output_type -> car.ControlAck
+	39, // 96: car.RaceControlService.RescindPenalty:output_type -> car.ControlAck
+	42, // 97: car.RaceControlService.GetRaceState:output_type -> car.RaceControlState
+	39, // 98: car.RaceControlService.CloseSession:output_type -> car.ControlAck
+	76, // [76:99] is the sub-list for method output_type
+	53, // [53:76] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
 }
 
 func init() { file_car_proto_init() }
@@ -1256,15 +4081,16 @@ func file_car_proto_init() {
 	if File_car_proto != nil {
 		return
 	}
+	file_car_proto_msgTypes[27].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_car_proto_rawDesc), len(file_car_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   14,
+			NumEnums:      7,
+			NumMessages:   36,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_car_proto_goTypes,
 		DependencyIndexes: file_car_proto_depIdxs,
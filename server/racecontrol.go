@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "server/proto"
+)
+
+// Admin service for running sessions without rebuilding the server
+type RaceControlServer struct {
+	pb.UnimplementedRaceControlServiceServer
+	cars  *CarServer
+	token string
+}
+
+func NewRaceControlServer(cars *CarServer, token string) *RaceControlServer {
+	return &RaceControlServer{cars: cars, token: token}
+}
+
+// Every call carries the admin token in the x-admin-token header
+func (r *RaceControlServer) authorize(ctx context.Context) error {
+	return checkAdminToken(ctx, r.token)
+}
+
+// Check a call's x-admin-token header against the admin token. Without an
+// admin token every call is refused.
+func checkAdminToken(ctx context.Context, adminToken string) error {
+	if adminToken == "" {
+		return status.Error(codes.PermissionDenied, "no admin token is set on the server")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(adminTokenHeader) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid admin token")
+}
+
+// Session named in the x-session-id header, the default session without one
+func (r *RaceControlServer) session(ctx context.Context) (*RaceSession, error) {
+	if err := r.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	sessionId := ""
+	if ids := md.Get(sessionHeader); len(ids) > 0 {
+		sessionId = ids[0]
+	}
+	return r.cars.session(sessionId)
+}
+
+// Run an action under the session lock and acknowledge it
+func (r *RaceControlServer) control(ctx context.Context, action func(s *RaceSession) error) (*pb.ControlAck, error) {
+	s, err := r.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := action(s); err != nil {
+		return &pb.ControlAck{
+			Accepted: false,
+			Message:  err.Error(),
+			GameTick: s.state.gameTick,
+		}, nil
+	}
+	return &pb.ControlAck{Accepted: true, GameTick: s.state.gameTick}, nil
+}
+
+// Whether cars can still be changed without disturbing a race
+func (st *RaceState) beforeStart() bool {
+	return st.phase == pb.RacePhase_PHASE_NOTREADY || st.phase == pb.RacePhase_PHASE_WAITING
+}
+
+// Whether the session is between the grid and the results
+func (st *RaceState) inProgress() bool {
+	return !st.beforeStart() && st.phase != pb.RacePhase_PHASE_RESULTS
+}
+
+// Rebuild the session with a new setup, keeping it open if it already was,
+// caller holds s.mu
+func (s *RaceSession) reconfigure(setup raceSetup) error {
+	started := s.state.phase != pb.RacePhase_PHASE_NOTREADY ||
+		slices.ContainsFunc(s.commands, func(command raceCommand) bool { return command.Kind == "start" })
+	if err := s.startSession(setup); err != nil {
+		return err
+	}
+	if started {
+		s.queueCommand(raceCommand{Kind: "start"})
+	}
+	return nil
+}
+
+// CreateSession RPC - replaces the session, which waits for StartSession
+func (r *RaceControlServer) CreateSession(ctx context.Context, req *pb.SessionConfig) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		setup, err := setupFromConfig(s.config.setup, req)
+		if err != nil {
+			return err
+		}
+		return s.startSession(setup)
+	})
+}
+
+// StartSession RPC - opens the session for check-ins
+func (r *RaceControlServer) StartSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if s.state.phase != pb.RacePhase_PHASE_NOTREADY || len(s.commands) > 0 {
+			return fmt.Errorf("session already started")
+		}
+		s.queueCommand(raceCommand{Kind: "start"})
+		return nil
+	})
+}
+
+// PauseSession RPC - freezes the simulation
+func (r *RaceControlServer) PauseSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if s.paused {
+			return fmt.Errorf("session already paused")
+		}
+		s.paused = true
+		return nil
+	})
+}
+
+// ResumeSession RPC - continues a paused simulation
+func (r *RaceControlServer) ResumeSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if !s.paused {
+			return fmt.Errorf("session not paused")
+		}
+		s.paused = false
+		s.signalInput()
+		return nil
+	})
+}
+
+// AbortSession RPC - ends the session without a follow-up
+func (r *RaceControlServer) AbortSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if s.state.phase == pb.RacePhase_PHASE_RESULTS {
+			return fmt.Errorf("session already over")
+		}
+		s.paused = false
+		s.queueCommand(raceCommand{Kind: "abort"})
+		return nil
+	})
+}
+
+// RestartSession RPC - runs the current session again from the start
+func (r *RaceControlServer) RestartSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if err := s.startSession(s.config.setup); err != nil {
+			return err
+		}
+		s.queueCommand(raceCommand{Kind: "start"})
+		return nil
+	})
+}
+
+// ChangeTrack RPC - moves the session to another track, not while it runs
+func (r *RaceControlServer) ChangeTrack(ctx context.Context, req *pb.TrackRequest) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if s.state.inProgress() {
+			return fmt.Errorf("session in progress, abort it first")
+		}
+
+		setup := s.config.setup
+		if err := setup.selectTrack(req.GetTrack(), req.GetGeneratedTrack()); err != nil {
+			return err
+		}
+		return s.reconfigure(setup)
+	})
+}
+
+// AddCar RPC - adds a car to the session before the start
+func (r *RaceControlServer) AddCar(ctx context.Context, req *pb.CarRequest) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		carId := req.GetCarId()
+		setup := s.config.setup
+		carIds := setup.carIds()
+
+		switch {
+		case !s.state.beforeStart():
+			return fmt.Errorf("cars can only be added before the start")
+		case carId == "" || carId == observersID:
+			return fmt.Errorf("invalid car id %q", carId)
+		case slices.Contains(carIds, carId):
+			return fmt.Errorf("car %s already in the session", carId)
+		}
+
+		setup.CarIds = append(slices.Clone(carIds), carId)
+		setup.NumCars = len(setup.CarIds)
+		return s.reconfigure(setup)
+	})
+}
+
+// RemoveCar RPC - takes a car out of the session before the start
+func (r *RaceControlServer) RemoveCar(ctx context.Context, req *pb.CarRequest) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		carId := req.GetCarId()
+		setup := s.config.setup
+		carIds := setup.carIds()
+
+		switch {
+		case !s.state.beforeStart():
+			return fmt.Errorf("cars can only be removed before the start")
+		case !slices.Contains(carIds, carId):
+			return fmt.Errorf("car %q not in the session", carId)
+		}
+
+		setup.CarIds = slices.DeleteFunc(slices.Clone(carIds), func(id string) bool { return id == carId })
+		setup.NumCars = len(setup.CarIds)
+		setup.Grid = slices.DeleteFunc(slices.Clone(setup.Grid), func(id string) bool { return id == carId })
+		return s.reconfigure(setup)
+	})
+}
+
+// IssuePenalty RPC - penalises a car on track, remaining_penalty in milliseconds.
+// Drive-throughs need no duration.
+func (r *RaceControlServer) IssuePenalty(ctx context.Context, req *pb.CarPenalty) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		state, ok := s.state.carStates[req.GetCarId()]
+		switch {
+		case !ok:
+			return fmt.Errorf("car %q not in the session", req.GetCarId())
+		case !onTrack(state):
+			return fmt.Errorf("car %s is not on track", req.GetCarId())
+		case req.GetKind() != pb.PenaltyKind_PENALTY_TIME && s.config.pitLane == nil:
+			return fmt.Errorf("track has no pit lane for a %v penalty", req.GetKind())
+		case req.GetKind() != pb.PenaltyKind_PENALTY_DRIVE_THROUGH && req.GetRemainingPenalty() <= 0:
+			return fmt.Errorf("penalty duration must be positive")
+		}
+
+		reason := req.GetReason()
+		if reason == "" {
+			reason = "race control"
+		}
+		s.queueCommand(raceCommand{
+			Kind:        "penalty",
+			CarId:       req.GetCarId(),
+			Reason:      reason,
+			Duration:    req.GetRemainingPenalty(),
+			PenaltyKind: req.GetKind(),
+		})
+		return nil
+	})
+}
+
+// RescindPenalty RPC - cancels what is left of a car's penalty
+func (r *RaceControlServer) RescindPenalty(ctx context.Context, req *pb.CarRequest) (*pb.ControlAck, error) {
+	return r.control(ctx, func(s *RaceSession) error {
+		if _, ok := s.state.penalties[req.GetCarId()]; !ok {
+			return fmt.Errorf("car %q has no penalty", req.GetCarId())
+		}
+		s.queueCommand(raceCommand{Kind: "rescind", CarId: req.GetCarId()})
+		return nil
+	})
+}
+
+// CloseSession RPC - remove a lobby and stop its tick loop
+func (r *RaceControlServer) CloseSession(ctx context.Context, req *pb.Empty) (*pb.ControlAck, error) {
+	s, err := r.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cars.closeSession(s.id); err != nil {
+		return &pb.ControlAck{Accepted: false, Message: err.Error()}, nil
+	}
+	return &pb.ControlAck{Accepted: true}, nil
+}
+
+// GetRaceState RPC - full internal state of the session
+func (r *RaceControlServer) GetRaceState(ctx context.Context, req *pb.Empty) (*pb.RaceControlState, error) {
+	s, err := r.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := s.state
+
+	update := st.createRaceUpdate()
+	update.RaceStatus.Paused = s.paused
+
+	cars := make([]*pb.CarDetails, 0, len(s.config.carInfos))
+	for _, car := range s.config.carInfos {
+		state := st.carStates[car.carId]
+		details := &pb.CarDetails{
+			State: proto.Clone(state.CarState).(*pb.CarState),
+			Info: &pb.CarInfo{
+				CarId:           car.carId,
+				Power:           car.power,
+				Weight:          car.weight,
+				FuelCapacity:    fuelCapacity,
+				FuelConsumption: car.fuelConsumption(),
+			},
+			CheckedIn:            state.checkedIn,
+			JumpStart:            state.jumpStart,
+			OffTrack:             state.offTrack,
+			TrackLimitViolations: state.trackLimitViolations,
+			LapValid:             state.lapValid,
+			BestLapTime:          state.bestLapTime,
+			LapTimes:             slices.Clone(state.lapTimes),
+		}
+		if input, ok := s.playerInput[car.carId]; ok {
+			details.Steering = input.steering
+			details.Throttle = input.throttle
+			details.Brake = input.brake
+		}
+		if in, ok := s.inputs[car.carId]; ok {
+			details.InputStats = in.stats()
+		}
+		cars = append(cars, details)
+	}
+
+	return &pb.RaceControlState{
+		Session:   s.sessionConfig(),
+		Phase:     st.phase,
+		Paused:    s.paused,
+		SimMode:   s.mode,
+		GameTick:  st.gameTick,
+		Update:    update,
+		Cars:      cars,
+		StateHash: st.hash(),
+	}, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// A car's input from Tick onwards, until the next change
+type replayInput struct {
+	Tick      int32   `json:"tick"`
+	CarId     string  `json:"car_id"`
+	Steering  float32 `json:"steering"`
+	Throttle  float32 `json:"throttle"`
+	Brake     float32 `json:"brake"`
+	PitRefuel float32 `json:"pit_refuel,omitempty"`
+	PitTyres  bool    `json:"pit_tyres,omitempty"`
+	CheckedIn bool    `json:"checked_in"`
+}
+
+// Everything needed to re-simulate a race bit for bit
+type replayData struct {
+	Setup  raceSetup     `json:"setup"`
+	Ticks  int32         `json:"ticks"`
+	Hash   string        `json:"hash"` // state hash after the last tick
+	Inputs []replayInput `json:"inputs"`
+
+	Commands []raceCommand `json:"commands,omitempty"`
+}
+
+// Records the inputs of a live race so it can be replayed later
+type replayRecorder struct {
+	dir      string
+	setup    raceSetup
+	last     map[string]PlayerInput
+	inputs   []replayInput
+	commands []raceCommand
+}
+
+func newReplayRecorder(dir string, setup raceSetup) *replayRecorder {
+	return &replayRecorder{
+		dir:   dir,
+		setup: setup,
+		last:  make(map[string]PlayerInput),
+	}
+}
+
+// Record the inputs and commands a tick was stepped with, keeping only input changes
+func (r *replayRecorder) record(tick int32, inputs map[string]PlayerInput, commands []raceCommand) {
+	for _, command := range commands {
+		command.Tick = tick
+		r.commands = append(r.commands, command)
+	}
+
+	carIds := make([]string, 0, len(inputs))
+	for carId := range inputs {
+		carIds = append(carIds, carId)
+	}
+	sort.Strings(carIds)
+
+	for _, carId := range carIds {
+		input := inputs[carId]
+		last := r.last[carId]
+		if input.steering == last.steering && input.throttle == last.throttle &&
+			input.brake == last.brake && input.pitRefuel == last.pitRefuel &&
+			input.pitTyres == last.pitTyres && input.checkedIn == last.checkedIn {
+			continue
+		}
+
+		r.last[carId] = input
+		r.inputs = append(r.inputs, replayInput{
+			Tick:      tick,
+			CarId:     carId,
+			Steering:  input.steering,
+			Throttle:  input.throttle,
+			Brake:     input.brake,
+			PitRefuel: input.pitRefuel,
+			PitTyres:  input.pitTyres,
+			CheckedIn: input.checkedIn,
+		})
+	}
+}
+
+// Write the replay up to the given state
+func (r *replayRecorder) save(state *RaceState) (string, error) {
+	data, err := json.Marshal(replayData{
+		Setup:  r.setup,
+		Ticks:  state.gameTick,
+		Hash:   fmt.Sprintf("%016x", state.hash()),
+		Inputs: r.inputs,
+
+		Commands: r.commands,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(r.dir, fmt.Sprintf("race-%d.json", r.setup.Seed))
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// Re-simulate a saved replay and check it ends in the recorded state
+func runReplay(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var replay replayData
+	if err := json.Unmarshal(raw, &replay); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	config, err := newRaceConfig(replay.Setup)
+	if err != nil {
+		return err
+	}
+
+	state := newRaceState(config)
+	inputs := make(map[string]PlayerInput)
+	next := 0
+	nextCommand := 0
+
+	for state.gameTick < replay.Ticks {
+		tick := state.gameTick + 1
+		for next < len(replay.Inputs) && replay.Inputs[next].Tick <= tick {
+			input := replay.Inputs[next]
+			inputs[input.CarId] = PlayerInput{
+				steering:  input.Steering,
+				throttle:  input.Throttle,
+				brake:     input.Brake,
+				pitRefuel: input.PitRefuel,
+				pitTyres:  input.PitTyres,
+				checkedIn: input.CheckedIn,
+			}
+			next++
+		}
+
+		var commands []raceCommand
+		for nextCommand < len(replay.Commands) && replay.Commands[nextCommand].Tick <= tick {
+			commands = append(commands, replay.Commands[nextCommand])
+			nextCommand++
+		}
+
+		state = stepRace(state, inputs, commands)
+	}
+
+	hash := fmt.Sprintf("%016x", state.hash())
+	log.Printf("Replayed %d ticks (seed %d), state hash %s", state.gameTick, replay.Setup.Seed, hash)
+
+	if hash != replay.Hash {
+		return fmt.Errorf("replay diverged: got hash %s, recorded %s", hash, replay.Hash)
+	}
+	return nil
+}
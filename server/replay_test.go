@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+
+	pb "server/proto"
+)
+
+const testRaceTicks = 3000 // 50 seconds, through the start and well into the race
+
+// Two-car race on the default track
+func testRaceSetup(t *testing.T) raceSetup {
+	t.Helper()
+	setup := raceSetup{Seed: 3, RaceType: pb.RaceType_RACEBYLAPS, NumCars: 2, Laps: 2}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	return setup
+}
+
+// Cars steering for the centerline ahead, with a penalty from race control
+// on the way, stepped the way the tick loop does and recorded
+func runTestRace(t *testing.T, setup raceSetup, recorder *replayRecorder) *RaceState {
+	t.Helper()
+	config, err := newRaceConfig(setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := newRaceState(config)
+	for state.gameTick < testRaceTicks {
+		var commands []raceCommand
+		switch state.gameTick {
+		case 0:
+			commands = []raceCommand{{Kind: "start"}}
+		case 2000:
+			commands = []raceCommand{{Kind: "penalty", CarId: "B", Reason: "test", Duration: 2000}}
+		}
+
+		inputs := testDriverInputs(state)
+		state = stepRace(state, inputs, commands)
+		if recorder != nil {
+			recorder.record(state.gameTick, inputs, commands)
+		}
+	}
+	return state
+}
+
+// Steer each car at a centerline point a little ahead, throttle once racing
+func testDriverInputs(st *RaceState) map[string]PlayerInput {
+	centerline := st.config.centerline
+	inputs := make(map[string]PlayerInput)
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		input := PlayerInput{checkedIn: true}
+		if st.running() {
+			target := centerline[(int(max(state.trackSegment, 0))+8)%len(centerline)]
+			bearing := math.Atan2(float64(target.centerY-state.Position.Y), float64(target.centerX-state.Position.X))
+			offset := math.Remainder(bearing*180/math.Pi-float64(state.Heading), 360)
+			input.steering = float32(max(-1, min(1, offset/float64(maxSteerAngle))))
+			input.throttle = 0.6
+		}
+		inputs[car.carId] = input
+	}
+	return inputs
+}
+
+func TestRaceDeterministic(t *testing.T) {
+	setup := testRaceSetup(t)
+	first := runTestRace(t, setup, nil)
+	second := runTestRace(t, setup, nil)
+
+	if first.phase != pb.RacePhase_PHASE_RACING {
+		t.Fatalf("race in %v after %d ticks, want racing", first.phase, testRaceTicks)
+	}
+	for carId, state := range first.carStates {
+		if state.covered <= 0 {
+			t.Errorf("car %s never got going", carId)
+		}
+	}
+	if first.hash() != second.hash() {
+		t.Errorf("state hash %016x then %016x for the same race", first.hash(), second.hash())
+	}
+}
+
+func TestReplayFile(t *testing.T) {
+	setup := testRaceSetup(t)
+	recorder := newReplayRecorder(t.TempDir(), setup)
+	state := runTestRace(t, setup, recorder)
+
+	path, err := recorder.save(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runReplay(path); err != nil {
+		t.Fatalf("replay of the recording: %v", err)
+	}
+
+	// Dropping the start command must show up as a divergence
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replay replayData
+	if err := json.Unmarshal(raw, &replay); err != nil {
+		t.Fatal(err)
+	}
+	if len(replay.Commands) != 2 {
+		t.Fatalf("%d commands recorded, want 2", len(replay.Commands))
+	}
+	replay.Commands = replay.Commands[1:]
+	raw, err = json.Marshal(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runReplay(path); err == nil {
+		t.Error("replay without the start matched the recording")
+	}
+}
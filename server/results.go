@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	pb "server/proto"
+)
+
+// Car ids in race order: disqualified cars last, then most laps covered
+// first, then who took the flag first once penalty time is added, then who
+// has covered most distance
+func (st *RaceState) raceOrder() []string {
+	n := int32(len(st.config.centerline))
+	order := make([]string, 0, len(st.config.carInfos))
+	for _, car := range st.config.carInfos {
+		order = append(order, car.carId)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a := st.carStates[order[i]]
+		b := st.carStates[order[j]]
+		if (a.disqualified == "") != (b.disqualified == "") {
+			return a.disqualified == ""
+		}
+		if a.covered < 0 || b.covered < 0 {
+			return b.covered < 0 && a.covered >= 0
+		}
+		if a.distance/n != b.distance/n {
+			return a.distance > b.distance
+		}
+		if a.finishTick > 0 || b.finishTick > 0 {
+			return a.finishTick > 0 && (b.finishTick == 0 || a.finishedAt() < b.finishedAt())
+		}
+		return a.distance > b.distance
+	})
+
+	return order
+}
+
+// Time a car has spent in the session
+func (st *RaceState) totalTime(state *CarStateExtended) float32 {
+	// Timed sessions add up the laps driven
+	if isTimedSession(st.config.setup.RaceType) {
+		total := float32(0)
+		for _, lapTime := range state.lapTimes {
+			total += lapTime
+		}
+		return total
+	}
+
+	if st.raceStartTick == 0 || !state.checkedIn {
+		return 0
+	}
+	end := st.gameTick
+	if state.finishTick > 0 {
+		end = state.finishTick
+	}
+	return float32(end-st.raceStartTick)*fixedDt + float32(state.timeAdded)/1000
+}
+
+// Race time at the flag in ticks, with penalty time added
+func (state *CarStateExtended) finishedAt() float32 {
+	return float32(state.finishTick) + float32(state.timeAdded)/float32(tickMillis)
+}
+
+// Classification of the session so far
+func (st *RaceState) results() []*pb.ResultEntry {
+	timed := isTimedSession(st.config.setup.RaceType)
+
+	var order []string
+	if timed {
+		order = st.classification()
+	} else {
+		order = st.raceOrder()
+	}
+
+	entries := make([]*pb.ResultEntry, 0, len(order))
+	for i, carId := range order {
+		state := st.carStates[carId]
+		entry := &pb.ResultEntry{
+			Position:     int32(i + 1),
+			CarId:        carId,
+			Status:       state.Status,
+			Laps:         state.Lap,
+			TotalTime:    st.totalTime(state),
+			BestLapTime:  state.bestLapTime,
+			Penalties:    state.penaltyCount,
+			PenaltyTime:  float32(state.penaltyTime) / 1000,
+			Disqualified: state.disqualified,
+		}
+
+		// Disqualified cars are not timed against the winner
+		if i > 0 && state.disqualified == "" {
+			winner := st.carStates[order[0]]
+			switch {
+			case timed:
+				if state.bestLapTime > 0 {
+					entry.Gap = state.bestLapTime - winner.bestLapTime
+				}
+			case state.Lap < winner.Lap:
+				entry.LapsBehind = winner.Lap - state.Lap
+			default:
+				entry.Gap = st.timeGap(state, winner)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// GetResults RPC - classification of a session, final once it is over
+func (c *CarServer) GetResults(ctx context.Context, req *pb.SessionRequest) (*pb.Results, error) {
+	s, err := c.session(req.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := s.state
+	return &pb.Results{
+		SessionId:      s.id,
+		RaceType:       s.config.setup.RaceType,
+		Final:          st.phase == pb.RacePhase_PHASE_RESULTS,
+		GameTick:       st.gameTick,
+		Classification: st.results(),
+	}, nil
+}
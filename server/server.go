@@ -7,106 +7,80 @@ import (
 	"time"
 )
 
-func NewCarServer() *CarServer {
-	carInfos := make([]CarInfo, numCars)
-	carStates := make(map[string]*CarStateExtended)
-	playerInput := make(map[string]*PlayerInput)
-	authTokens := make(map[string]string)
-	penalties := make(map[string]*pb.CarPenalty)
-
-	// Load track from CSV
-	track, err := loadTrackFromCSV("./tracks/Barcelona.csv")
+func NewCarServer(setup raceSetup, mode pb.SimulationMode, recordDir, adminToken string) *CarServer {
+	c := &CarServer{
+		sessions:   make(map[string]*RaceSession),
+		recordDir:  recordDir,
+		adminToken: adminToken,
+	}
+
+	s, err := newRaceSession(defaultSession, setup, mode, recordDir)
 	if err != nil {
 		log.Fatalf("Failed to load track: %v", err)
 	}
+	c.sessions[s.id] = s
 
-	// Start cars at first track point with staggered positions
-	startX := track.LeftBoundary[0].X
-	startY := track.LeftBoundary[0].Y
-
-	for i := 0; i < numCars; i++ {
-		carId := string(rune('A' + i))
-		carInfos[i] = CarInfo{
-			carId:  carId,
-			power:  float32(80 + i*5),
-			weight: float32(1000 + i*50),
-			x:      startX,
-			y:      startY + float32(i*10),
-			z:      0.0,
-		}
+	go c.expireLobbies()
 
-		carStates[carId] = &CarStateExtended{
-			CarState: &pb.CarState{
-				CarId:  carId,
-				Status: pb.CarStatus_WAITING,
-				Position: &pb.Point3D{
-					X: carInfos[i].x,
-					Y: carInfos[i].y,
-					Z: carInfos[i].z,
-				},
-				Heading: 0.0,
-				Speed:   0.0,
-				Lap:     0,
-			},
-			lastProgress:    0.0,
-			crossedFinish:   false,
-			bestLapTime:     0.0,
-			currentLapStart: time.Now(),
-			lapTimes:        make([]float32, 0),
-		}
-
-		playerInput[carId] = &PlayerInput{}
+	return c
+}
 
-		authTokens[carId] = "demo-token-" + carId
-		log.Printf("Car %s auth token: %s", carId, authTokens[carId])
+// Create a session and start its tick loop
+func newRaceSession(id string, setup raceSetup, mode pb.SimulationMode, recordDir string) (*RaceSession, error) {
+	config, err := newRaceConfig(setup)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Loaded track '%s' with %d points", track.Name, len(track.LeftBoundary))
+	log.Printf("Session %s: loaded track '%s' with %d points", id, config.track.Name, len(config.track.LeftBoundary))
 
-	// Set race type (can be configured)
-	raceType := pb.RaceType_RACEBYLAPS
-	raceLaps := int32(totalLaps)
-	raceTimeRemaining := int32(0)
+	s := &RaceSession{
+		id:          id,
+		config:      config,
+		state:       newRaceState(config),
+		playerInput: make(map[string]*PlayerInput),
+		authTokens:  make(map[string]string),
+		clients:     make(map[chan *pb.RaceUpdate]struct{}),
+		drivers:     make(map[chan *pb.DriveUpdate]string),
 
-	if raceType == pb.RaceType_RACEBYTIME {
-		raceTimeRemaining = raceTime
+		mode:         mode,
+		checkedIn:    make(map[string]bool),
+		inputTick:    make(map[string]int32),
+		inputs:       make(map[string]*carInputs),
+		inputArrived: make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		lastActive:   time.Now(),
 	}
 
-	s := &CarServer{
-		carInfos:    carInfos,
-		carStates:   carStates,
-		playerInput: playerInput,
-		authTokens:  authTokens,
-		penalties:   penalties,
-		raceStatus: &pb.RaceStatus{
-			Status:    "racing",
-			TotalLaps: raceLaps,
-			GameTick:  0,
-		},
-		raceStarted:  time.Now(),
-		clients:      make(map[chan *pb.RaceUpdate]struct{}),
-		gameTick:     0,
-		track:        track,
-		raceType:     raceType,
-		raceLaps:     raceLaps,
-		raceTimeLeft: raceTimeRemaining,
-	}
+	s.syncCars()
 
-	// Set all cars to RACING status
-	for _, state := range carStates {
-		state.Status = pb.CarStatus_RACING
-		state.currentLapStart = time.Now()
+	if recordDir != "" {
+		s.recorder = newReplayRecorder(recordDir, setup)
 	}
 
-	go s.physicsLoop()
+	// Open the session for check-ins straight away
+	s.queueCommand(raceCommand{Kind: "start"})
 
-	return s
+	go s.run()
+
+	return s, nil
 }
 
 // CheckIn RPC - handles player registration and returns static data
-func (s *CarServer) CheckIn(ctx context.Context, req *pb.RegisterPlayer) (*pb.CheckInResponse, error) {
-	carId := req.GetCarId()
+func (c *CarServer) CheckIn(ctx context.Context, req *pb.RegisterPlayer) (*pb.CheckInResponse, error) {
+	s, err := c.session(req.GetSessionId())
+	if err != nil {
+		return &pb.CheckInResponse{
+			Accepted: false,
+			Message:  "Session not found",
+		}, nil
+	}
+
+	return s.checkIn(req.GetCarId()), nil
+}
 
+// Check a car or spectator in to the session
+func (s *RaceSession) checkIn(carId string) *pb.CheckInResponse {
 	// Check if this is a spectator
 	isSpectator := false
 	token := ""
@@ -128,16 +102,33 @@ func (s *CarServer) CheckIn(ctx context.Context, req *pb.RegisterPlayer) (*pb.Ch
 		return &pb.CheckInResponse{
 			Accepted: false,
 			Message:  "Car ID not found",
-		}, nil
+		}
 	}
 
 	// In production, validate password here
 	// For demo, we accept all check-ins
 
-	s.mu.RLock()
-	track := s.track
-	raceType := s.raceType
-	s.mu.RUnlock()
+	s.mu.Lock()
+	if !isSpectator {
+		s.checkedIn[carId] = true
+		s.resetSequence(carId)
+	}
+	s.lastActive = time.Now()
+	track := s.config.track
+	raceType := s.config.setup.RaceType
+	mode := s.mode
+	cars := make([]*pb.CarInfo, 0, len(s.config.carInfos))
+	for _, car := range s.config.carInfos {
+		cars = append(cars, &pb.CarInfo{
+			CarId:           car.carId,
+			Power:           car.power,
+			Weight:          car.weight,
+			FuelCapacity:    fuelCapacity,
+			FuelConsumption: car.fuelConsumption(),
+		})
+	}
+	s.mu.Unlock()
+	s.signalInput()
 
 	message := "Welcome to the race!"
 	if isSpectator {
@@ -151,13 +142,117 @@ func (s *CarServer) CheckIn(ctx context.Context, req *pb.RegisterPlayer) (*pb.Ch
 		IsSpectator: isSpectator,
 		Track:       track,
 		Race:        raceType,
-	}, nil
+		Cars:        cars,
+		SimMode:     mode,
+		SessionId:   s.id,
+		CarId:       carId,
+	}
 }
 
 // Validate auth token
-func (s *CarServer) validateToken(carId, token string) bool {
+func (s *RaceSession) validateToken(carId, token string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	expected, ok := s.authTokens[carId]
 	return ok && token == expected
 }
+
+// Current game tick
+func (s *RaceSession) currentTick() int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.gameTick
+}
+
+// Give every car in the session an input slot and auth token, and forget
+// cars that have left it, caller holds s.mu
+func (s *RaceSession) syncCars() {
+	inSession := make(map[string]bool, len(s.config.carInfos))
+	for _, car := range s.config.carInfos {
+		inSession[car.carId] = true
+		if _, ok := s.authTokens[car.carId]; ok {
+			continue
+		}
+
+		s.playerInput[car.carId] = &PlayerInput{}
+		s.authTokens[car.carId] = "demo-token-" + car.carId
+		log.Printf("Car %s auth token: %s", car.carId, s.authTokens[car.carId])
+	}
+
+	for carId := range s.authTokens {
+		if !inSession[carId] {
+			delete(s.authTokens, carId)
+			delete(s.playerInput, carId)
+			delete(s.checkedIn, carId)
+			delete(s.inputs, carId)
+		}
+	}
+}
+
+// Queue a race control action for the next tick, caller holds s.mu
+func (s *RaceSession) queueCommand(command raceCommand) {
+	s.commands = append(s.commands, command)
+	s.signalInput()
+}
+
+// Replace the current session with a new one, reloading the track only if it
+// changed, caller holds s.mu. The new session waits for a start command.
+func (s *RaceSession) startSession(setup raceSetup) error {
+	if setup.sameTrack(s.config.setup) {
+		s.config = buildRaceConfig(setup, s.config.track)
+	} else {
+		config, err := newRaceConfig(setup)
+		if err != nil {
+			return err
+		}
+		s.config = config
+		log.Printf("Loaded track '%s' with %d points", config.track.Name, len(config.track.LeftBoundary))
+	}
+
+	s.state = newRaceState(s.config)
+	s.inputTick = make(map[string]int32)
+	s.resetInputs()
+	// Controls held at the end of the last race would otherwise be applied
+	// from the first tick of this one, jump-starting every car on throttle
+	for carId := range s.playerInput {
+		s.playerInput[carId] = &PlayerInput{}
+	}
+	s.commands = nil
+	s.paused = false
+	s.racesRun++
+	s.syncCars()
+
+	if s.recorder != nil {
+		s.recorder = newReplayRecorder(s.recorder.dir, setup)
+	}
+
+	log.Printf("Session %s: starting race %d, %v (seed %d)", s.id, s.racesRun+1, setup.RaceType, setup.Seed)
+	return nil
+}
+
+// Qualifying sets the grid for the race that follows it, caller holds s.mu
+func (s *RaceSession) startRaceFromQualifying() {
+	grid := s.state.classification()
+	log.Printf("Qualifying classification: %v", grid)
+
+	setup := s.config.setup
+	setup.RaceType = pb.RaceType_RACEBYLAPS
+	setup.Laps = totalLaps
+	setup.RaceTime = raceTime
+	setup.Grid = grid
+	s.startNextRace(setup)
+}
+
+// Start the next race of the session straight away. A setup whose track
+// cannot be built runs the last setup again, rather than leaving the session
+// in the results. Caller holds s.mu.
+func (s *RaceSession) startNextRace(setup raceSetup) {
+	if err := s.startSession(setup); err != nil {
+		log.Printf("Session %s: next race failed to start, running the last setup again: %v", s.id, err)
+		if err := s.startSession(s.config.setup); err != nil {
+			log.Printf("Session %s: failed to restart: %v", s.id, err)
+			return
+		}
+	}
+	s.queueCommand(raceCommand{Kind: "start"})
+}
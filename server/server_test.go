@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "server/proto"
+)
+
+// Short hot-lap session on the default track, its tick loop running until
+// the test ends
+func testSession(t *testing.T) (*RaceSession, raceSetup) {
+	t.Helper()
+	setup := raceSetup{Seed: 1, RaceType: pb.RaceType_HOTLAP, NumCars: 2, Laps: 1, RaceTime: 60}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newRaceSession("test", setup, pb.SimulationMode_REALTIME, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { close(s.done) })
+	return s, setup
+}
+
+func TestControlsClearedOnRestart(t *testing.T) {
+	s, setup := testSession(t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	carId := s.config.carInfos[0].carId
+	s.setInput(carId, &pb.PlayerInput{Steering: 0.3, Throttle: 1, PitTyres: true})
+	if err := s.startSession(setup); err != nil {
+		t.Fatal(err)
+	}
+	if got := *s.playerInput[carId]; got != (PlayerInput{}) {
+		t.Errorf("controls after the restart %+v, want none", got)
+	}
+}
+
+func TestNextRaceFallsBack(t *testing.T) {
+	s, setup := testSession(t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := setup
+	next.Seed++
+	next.TrackFile = "missing.csv"
+	racesRun := s.racesRun
+	s.startNextRace(next)
+
+	if s.config.setup.TrackFile != setup.TrackFile || s.config.setup.Seed != setup.Seed {
+		t.Errorf("setup after a failed start %+v, want the last one", s.config.setup)
+	}
+	if s.racesRun != racesRun+1 || s.state.phase != pb.RacePhase_PHASE_NOTREADY {
+		t.Errorf("race %d in %v after a failed start, want a new race", s.racesRun, s.state.phase)
+	}
+	if len(s.commands) != 1 || s.commands[0].Kind != "start" {
+		t.Errorf("commands %v, want a start", s.commands)
+	}
+}
+
+func TestFastLoopWaitsForCars(t *testing.T) {
+	setup := raceSetup{Seed: 1, RaceType: pb.RaceType_HOTLAP, NumCars: 2, Laps: 1, RaceTime: 60}
+	if err := setup.selectTrack(defaultTrack, nil); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newRaceSession("fast", setup, pb.SimulationMode_FAST, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(s.done)
+
+	time.Sleep(50 * time.Millisecond)
+	if tick := s.currentTick(); tick > 1 {
+		t.Errorf("fast session at tick %d with no cars, want it waiting", tick)
+	}
+
+	s.checkIn(s.config.carInfos[0].carId)
+	time.Sleep(50 * time.Millisecond)
+	if tick := s.currentTick(); tick <= 1 {
+		t.Errorf("fast session at tick %d after a check-in, want it running", tick)
+	}
+}
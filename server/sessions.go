@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log"
+	"sort"
+
+	pb "server/proto"
+)
+
+// Hot lap and qualifying are timed sessions: out-lap first, ranked by best lap
+func isTimedSession(raceType pb.RaceType) bool {
+	return raceType == pb.RaceType_HOTLAP || raceType == pb.RaceType_QUALY
+}
+
+// Hot lap: once the track is free, the first waiting car (in grid order)
+// that applies throttle goes out for its run
+func (st *RaceState) releaseHotlapCar(inputs map[string]PlayerInput) {
+	if st.raceTimeLeft <= 0 {
+		return
+	}
+
+	for _, car := range st.config.carInfos {
+		if onTrack(st.carStates[car.carId]) {
+			return
+		}
+	}
+
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		if state.Status != pb.CarStatus_WAITING || inputs[car.carId].throttle <= 0 {
+			continue
+		}
+
+		state.Status = pb.CarStatus_RACING
+		state.lapStartTick = st.gameTick
+		log.Printf("Car %s leaves the garage for its hot-lap run", car.carId)
+		return
+	}
+}
+
+// Car ids ordered by best valid lap, cars without a time last in grid order
+func (st *RaceState) classification() []string {
+	order := make([]string, 0, len(st.config.carInfos))
+	for _, car := range st.config.carInfos {
+		order = append(order, car.carId)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a := st.carStates[order[i]].bestLapTime
+		b := st.carStates[order[j]].bestLapTime
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+
+	return order
+}
+
+// Intervals for timed sessions: gaps in best lap time
+func (st *RaceState) sessionIntervals() ([]*pb.CarInterval, []*pb.CarInterval) {
+	order := st.classification()
+	toLeader := make([]*pb.CarInterval, 0, len(order))
+	forPosition := make([]*pb.CarInterval, 0, len(order))
+	if len(order) == 0 {
+		return toLeader, forPosition
+	}
+
+	leaderBest := st.carStates[order[0]].bestLapTime
+	prevBest := leaderBest
+
+	for i, carId := range order {
+		state := st.carStates[carId]
+		intervalToLeader := float32(0)
+		intervalForPosition := float32(0)
+		if state.bestLapTime > 0 {
+			intervalToLeader = state.bestLapTime - leaderBest
+			intervalForPosition = state.bestLapTime - prevBest
+			prevBest = state.bestLapTime
+		}
+
+		toLeader = append(toLeader, &pb.CarInterval{
+			CarId:    carId,
+			Position: int32(i + 1),
+			Laps:     state.Lap,
+			Interval: intervalToLeader,
+		})
+		forPosition = append(forPosition, &pb.CarInterval{
+			CarId:    carId,
+			Position: int32(i + 1),
+			Laps:     state.Lap,
+			Interval: intervalForPosition,
+		})
+	}
+
+	return toLeader, forPosition
+}
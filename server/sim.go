@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	pb "server/proto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Fixed simulation timestep, whatever drives the ticks
+const (
+	fixedDt    = float32(updateRate) / float32(time.Second)
+	tickMillis = int32(updateRate / time.Millisecond)
+)
+
+// Load or generate the track and build the grid for a race
+func newRaceConfig(setup raceSetup) (*RaceConfig, error) {
+	track, err := setupTrack(setup)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildRaceConfig(setup, track), nil
+}
+
+// Build the grid for a race on an already loaded track
+func buildRaceConfig(setup raceSetup, track *pb.TrackInfo) *RaceConfig {
+	centerline := buildCenterline(track)
+	geometry := newTrackGeometry(centerline)
+
+	carIds := setup.carIds()
+	carInfos := make([]CarInfo, len(carIds))
+	for i, carId := range carIds {
+		power, weight := carSpecs(carId, i)
+
+		// Hot-lap runs all start from the line
+		slot := gridSlot(setup.Grid, carId, i)
+		if setup.RaceType == pb.RaceType_HOTLAP {
+			slot = 0
+		}
+		x, y, heading := gridPosition(centerline, slot)
+		z := geometry.heightAt(geometry.locate(&pb.Point3D{X: x, Y: y}, -1))
+
+		carInfos[i] = CarInfo{
+			carId:   carId,
+			power:   power,
+			weight:  weight,
+			x:       x,
+			y:       y,
+			z:       z,
+			heading: heading,
+		}
+	}
+
+	var pitLane *trackGeometry
+	if track.PitLane != nil {
+		pitLane = newLaneGeometry(centerlineBetween(track.PitLane.LeftBoundary, track.PitLane.RightBoundary))
+	}
+
+	return &RaceConfig{
+		setup:      setup,
+		track:      track,
+		centerline: centerline,
+		geometry:   geometry,
+		pitLane:    pitLane,
+		surfaces:   newSurfaceMap(track),
+		carInfos:   carInfos,
+	}
+}
+
+// Grid slots line up behind the start line, staggered left and right
+func gridPosition(centerline []TrackPoint, slot int) (x, y, heading float32) {
+	start := centerline[0]
+	next := centerline[1%len(centerline)]
+
+	dx := next.centerX - start.centerX
+	dy := next.centerY - start.centerY
+	length := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if length > 0 {
+		dx /= length
+		dy /= length
+	}
+
+	// Left of the direction of travel is (-dy, dx)
+	side := gridStagger
+	if slot%2 == 1 {
+		side = -gridStagger
+	}
+	back := gridSpacing * float32(slot+1)
+
+	x = start.centerX - dx*back - dy*side
+	y = start.centerY - dy*back + dx*side
+	heading = float32(math.Atan2(float64(dy), float64(dx)) * 180 / math.Pi)
+	if heading < 0 {
+		heading += 360
+	}
+	return x, y, heading
+}
+
+// Car ids in the race
+func (setup raceSetup) carIds() []string {
+	if len(setup.CarIds) > 0 {
+		return setup.CarIds
+	}
+
+	carIds := make([]string, setup.NumCars)
+	for i := range carIds {
+		carIds[i] = string(rune('A' + i))
+	}
+	return carIds
+}
+
+// Number of timing sectors
+func (setup raceSetup) sectors() int32 {
+	if setup.Sectors > 0 {
+		return setup.Sectors
+	}
+	return numSectors
+}
+
+// Number of lap counting gates
+func (setup raceSetup) checkpoints() int32 {
+	if setup.Checkpoints > 0 {
+		return setup.Checkpoints
+	}
+	return numCheckpoints
+}
+
+// Specs follow the car id, so they survive cars being added and removed
+func carSpecs(carId string, index int) (power, weight float32) {
+	n := index
+	if len(carId) == 1 && carId[0] >= 'A' && carId[0] <= 'Z' {
+		n = int(carId[0] - 'A')
+	}
+	return float32(80 + n*5), float32(1000 + n*50)
+}
+
+// Grid position of a car, cars missing from the grid line up behind it
+func gridSlot(grid []string, carId string, index int) int {
+	if len(grid) == 0 {
+		return index
+	}
+	for slot, gridCarId := range grid {
+		if gridCarId == carId {
+			return slot
+		}
+	}
+	return len(grid) + index
+}
+
+// State at tick 0, with every car on the grid
+func newRaceState(config *RaceConfig) *RaceState {
+	carStates := make(map[string]*CarStateExtended)
+	for _, car := range config.carInfos {
+		carStates[car.carId] = &CarStateExtended{
+			CarState: &pb.CarState{
+				CarId:  car.carId,
+				Status: pb.CarStatus_NOTREADY,
+				Position: &pb.Point3D{
+					X: car.x,
+					Y: car.y,
+					Z: car.z,
+				},
+				Heading: car.heading,
+				Speed:   0.0,
+				Lap:     0,
+				Fuel:    config.setup.startFuel(),
+			},
+			lapValid:        true,
+			lapTimes:        make([]float32, 0),
+			covered:         -1,
+			trackSegment:    -1,
+			pitSegment:      -1,
+			bestSectorTimes: make([]float32, config.setup.sectors()),
+		}
+	}
+
+	raceTimeLeft := int32(0)
+	if config.setup.RaceType != pb.RaceType_RACEBYLAPS {
+		raceTimeLeft = config.setup.RaceTime
+	}
+
+	return &RaceState{
+		config:       config,
+		raceTimeLeft: raceTimeLeft,
+		phase:        pb.RacePhase_PHASE_NOTREADY,
+		carStates:    carStates,
+		penalties:    make(map[string]*pb.CarPenalty),
+		rng:          *rand.NewPCG(config.setup.Seed, config.setup.Seed),
+	}
+}
+
+// Deep copy, so that stepping never touches the previous state
+func (st *RaceState) clone() *RaceState {
+	next := *st
+
+	next.carStates = make(map[string]*CarStateExtended, len(st.carStates))
+	for carId, state := range st.carStates {
+		car := *state
+		car.CarState = proto.Clone(state.CarState).(*pb.CarState)
+		car.lapTimes = append([]float32(nil), state.lapTimes...)
+		car.sectorTimes = append([]float32(nil), state.sectorTimes...)
+		car.lastSectorTimes = append([]float32(nil), state.lastSectorTimes...)
+		car.bestSectorTimes = append([]float32(nil), state.bestSectorTimes...)
+
+		// Only the lap being driven is written to, earlier laps can be shared
+		car.timingLine = append([][]int32(nil), state.timingLine...)
+		if n := len(car.timingLine); n > 0 {
+			car.timingLine[n-1] = append([]int32(nil), car.timingLine[n-1]...)
+		}
+		next.carStates[carId] = &car
+	}
+
+	next.penalties = make(map[string]*pb.CarPenalty, len(st.penalties))
+	for carId, penalty := range st.penalties {
+		next.penalties[carId] = proto.Clone(penalty).(*pb.CarPenalty)
+	}
+
+	next.contacts = nil
+
+	return &next
+}
+
+// Advance the race by one fixed timestep. The result depends only on prev,
+// inputs and race control commands; cars are always processed in grid order.
+func stepRace(prev *RaceState, inputs map[string]PlayerInput, commands []raceCommand) *RaceState {
+	st := prev.clone()
+	config := st.config
+	st.gameTick++
+
+	for _, command := range commands {
+		st.applyCommand(command)
+	}
+
+	st.checkInCars(inputs)
+
+	if st.phase == pb.RacePhase_PHASE_GRID || st.phase == pb.RacePhase_PHASE_START_LIGHTS {
+		st.watchForJumpStarts(inputs)
+	}
+
+	if st.running() {
+		// Update the clock for time-limited races and sessions
+		if config.setup.RaceType != pb.RaceType_RACEBYLAPS {
+			elapsed := float32(st.gameTick-st.raceStartTick) * fixedDt
+			st.raceTimeLeft = max(0, config.setup.RaceTime-int32(elapsed))
+		}
+
+		if config.setup.RaceType == pb.RaceType_HOTLAP {
+			st.releaseHotlapCar(inputs)
+		}
+
+		st.updateAero()
+
+		for _, car := range config.carInfos {
+			state := st.carStates[car.carId]
+
+			// Update timers of penalties served in place
+			if penalty, hasPenalty := st.penalties[car.carId]; hasPenalty && penalty.Kind == pb.PenaltyKind_PENALTY_TIME {
+				penalty.RemainingPenalty -= tickMillis
+				if penalty.RemainingPenalty <= 0 {
+					delete(st.penalties, car.carId)
+					state.Status = pb.CarStatus_RACING
+				} else {
+					state.Status = pb.CarStatus_SERVINGPENALTY
+				}
+			}
+
+			// Only update physics if car is racing or in the pits (not serving penalty or finished)
+			if state.Status == pb.CarStatus_RACING || state.Status == pb.CarStatus_INPITS {
+				st.updateCarPhysics(car, state, inputs[car.carId])
+			}
+		}
+
+		st.resolveCollisions()
+	}
+
+	st.updatePhase()
+
+	return st
+}
+
+// Apply a race control action
+func (st *RaceState) applyCommand(command raceCommand) {
+	state := st.carStates[command.CarId]
+
+	switch command.Kind {
+	case "start":
+		if st.phase == pb.RacePhase_PHASE_NOTREADY {
+			st.setPhase(pb.RacePhase_PHASE_WAITING)
+		}
+
+	case "abort":
+		if st.phase != pb.RacePhase_PHASE_RESULTS {
+			st.aborted = true
+			st.setPhase(pb.RacePhase_PHASE_RESULTS)
+		}
+
+	case "penalty":
+		// Only cars out on track can serve a penalty
+		if state != nil && onTrack(state) {
+			st.issuePenaltyKind(state, command.PenaltyKind, command.Reason, command.Duration)
+		}
+
+	case "rescind":
+		if penalty, hasPenalty := st.penalties[command.CarId]; hasPenalty {
+			state.penaltyTime -= penalty.RemainingPenalty
+			delete(st.penalties, command.CarId)
+			if state.Status == pb.CarStatus_SERVINGPENALTY {
+				state.Status = pb.CarStatus_RACING
+			}
+			// A car stopped for a stop-go is released
+			if state.stopGo {
+				state.stopGo = false
+				state.PitStopLeft = 0
+			}
+			log.Printf("Penalty for car %s rescinded", command.CarId)
+		}
+	}
+}
+
+// Fingerprint of the race outcome, equal only for bit-identical states
+func (st *RaceState) hash() uint64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, []int32{st.gameTick, int32(st.phase)})
+
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		binary.Write(h, binary.LittleEndian, []float32{
+			state.Position.X, state.Position.Y, state.Position.Z,
+			state.Heading, state.Speed,
+		})
+		binary.Write(h, binary.LittleEndian, []int32{state.Lap, int32(state.Status), state.distance})
+		binary.Write(h, binary.LittleEndian, state.lapTimes)
+	}
+
+	return h.Sum64()
+}
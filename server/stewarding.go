@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	pb "server/proto"
+)
+
+// Issue a penalty the way this track serves them
+func (st *RaceState) issuePenalty(state *CarStateExtended, reason string, duration int32) {
+	st.issuePenaltyKind(state, st.penaltyKind(), reason, duration)
+}
+
+// Issue a penalty, stacking on top of one not yet served. Stacked penalties
+// are served the stricter way.
+func (st *RaceState) issuePenaltyKind(state *CarStateExtended, kind pb.PenaltyKind, reason string, duration int32) {
+	penalty, hasPenalty := st.penalties[state.CarId]
+	if hasPenalty {
+		penalty.RemainingPenalty += duration
+		penalty.Reason = reason
+		penalty.GameTick = st.gameTick
+		penalty.Kind = max(penalty.Kind, kind)
+	} else {
+		penalty = &pb.CarPenalty{
+			CarId:            state.CarId,
+			Reason:           reason,
+			GameTick:         st.gameTick,
+			RemainingPenalty: duration,
+			Kind:             kind,
+		}
+		st.penalties[state.CarId] = penalty
+	}
+
+	// Only time penalties stop the car where it is
+	if penalty.Kind == pb.PenaltyKind_PENALTY_TIME {
+		state.Status = pb.CarStatus_SERVINGPENALTY
+	} else if state.Status == pb.CarStatus_SERVINGPENALTY {
+		state.Status = pb.CarStatus_RACING
+	}
+	state.penaltyCount++
+	state.penaltyTime += duration
+
+	log.Printf("Car %s penalised %.1fs (%v): %s", state.CarId, float32(duration)/1000, penalty.Kind, reason)
+}
+
+// Check the car against the track edges and penalise repeated violations
+func (st *RaceState) checkTrackLimits(state *CarStateExtended, excess float32) {
+	if excess <= trackLimitsMargin {
+		state.offTrack = false
+		state.cutTrack = false
+		return
+	}
+
+	// Count each excursion once, on the tick the car leaves the track
+	if !state.offTrack {
+		state.offTrack = true
+		state.lapValid = false
+		state.trackLimitViolations++
+
+		if state.trackLimitViolations > trackLimitsWarnings {
+			reason := fmt.Sprintf("track limits (%d violations)", state.trackLimitViolations)
+			st.issuePenalty(state, reason, trackLimitsPenalty)
+		} else {
+			log.Printf("Car %s track limits warning %d/%d",
+				state.CarId, state.trackLimitViolations, trackLimitsWarnings)
+		}
+	}
+
+	// Going far off is cutting the track, penalised once per excursion
+	if excess > trackLimitsCutLimit && !state.cutTrack {
+		state.cutTrack = true
+		st.issuePenalty(state, "cutting the track", cutTrackPenalty)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+)
+
+// Fuel on board at the start of a race
+func (setup raceSetup) startFuel() float32 {
+	if setup.Fuel > 0 {
+		return min(setup.Fuel, fuelCapacity)
+	}
+	return startFuel
+}
+
+// Fuel used per second at full throttle
+func (car CarInfo) fuelConsumption() float32 {
+	return car.power * powerScale * fuelPerJoule
+}
+
+// Weight of the car with the fuel on board
+func (state *CarStateExtended) mass(car CarInfo) float32 {
+	return car.weight + state.Fuel
+}
+
+// Tyre friction coefficient, falling as the tyres wear
+func (state *CarStateExtended) grip() float32 {
+	return tyreGrip * (1 - tyreGripLoss*state.TyreWear)
+}
+
+// Wear the tyres over the distance driven, faster the closer they work to
+// their grip limit. load is the tyre force as a fraction of that limit.
+func wearTyres(state *CarStateExtended, load, distance float32) {
+	state.TyreWear = min(1, state.TyreWear+distance*tyreWearRate*(0.5+0.5*min(load, 1)))
+}
+
+// Burn the fuel for the engine work done this tick
+func burnFuel(car CarInfo, state *CarStateExtended, throttle float32) {
+	if throttle <= 0 || state.Fuel <= 0 {
+		return
+	}
+
+	state.Fuel -= car.fuelConsumption() * throttle * fixedDt
+	if state.Fuel <= 0 {
+		state.Fuel = 0
+		log.Printf("Car %s has run out of fuel", state.CarId)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math"
+
+	pb "server/proto"
+)
+
+// How each surface drives
+func surfaceProperties() []*pb.SurfaceProperties {
+	return []*pb.SurfaceProperties{
+		{Surface: pb.Surface_SURFACE_ASPHALT, Friction: 1},
+		{Surface: pb.Surface_SURFACE_KERB, Friction: kerbFriction, Drag: kerbDrag},
+		{Surface: pb.Surface_SURFACE_GRASS, Friction: grassFriction, Drag: grassDrag},
+		{Surface: pb.Surface_SURFACE_GRAVEL, Friction: gravelFriction, Drag: gravelDrag},
+	}
+}
+
+// Run-off for a track, from the curvature at each point: kerbs on both edges
+// of corners, gravel on the outside of tight ones and grass everywhere else
+func surfaceZones(curvature []float32) []*pb.SurfaceZone {
+	n := len(curvature)
+
+	var zones []*pb.SurfaceZone
+	for _, left := range []bool{true, false} {
+		kerbs := make([]surfaceStrip, n)
+		runoff := make([]surfaceStrip, n)
+		for i, k := range curvature {
+			// Run-off starts behind the kerb, or right at the edge without one
+			runoff[i] = surfaceStrip{surface: pb.Surface_SURFACE_GRASS}
+			if float32(math.Abs(float64(k))) > kerbCurvature {
+				kerbs[i] = surfaceStrip{surface: pb.Surface_SURFACE_KERB}
+				runoff[i].inner = kerbWidth
+			}
+
+			// Positive curvature turns left, so the left edge is the inside
+			outside := (k > 0) != left
+			if outside && float32(math.Abs(float64(k))) > gravelCurvature {
+				runoff[i].surface = pb.Surface_SURFACE_GRAVEL
+			}
+		}
+
+		for _, run := range surfaceRuns(kerbs) {
+			if run.strip.surface == pb.Surface_SURFACE_KERB {
+				zones = append(zones, run.zone(left, kerbWidth))
+			}
+		}
+		for _, run := range surfaceRuns(runoff) {
+			zones = append(zones, run.zone(left, runoffWidth))
+		}
+	}
+
+	return zones
+}
+
+// Surface beside one boundary point, starting inner metres past the edge
+type surfaceStrip struct {
+	surface pb.Surface
+	inner   float32
+}
+
+// A stretch of points with the same strip, end inclusive
+type surfaceRun struct {
+	strip      surfaceStrip
+	start, end int32
+}
+
+func (run surfaceRun) zone(left bool, outer float32) *pb.SurfaceZone {
+	return &pb.SurfaceZone{
+		Surface: run.strip.surface,
+		Left:    left,
+		Start:   run.start,
+		End:     run.end,
+		Inner:   run.strip.inner,
+		Outer:   outer,
+	}
+}
+
+// Split a loop of per-point strips into runs, joining the last run to the
+// first when they carry on across the start line
+func surfaceRuns(strips []surfaceStrip) []surfaceRun {
+	var runs []surfaceRun
+	for i, strip := range strips {
+		if len(runs) > 0 && runs[len(runs)-1].strip == strip {
+			runs[len(runs)-1].end = int32(i)
+			continue
+		}
+		runs = append(runs, surfaceRun{strip: strip, start: int32(i), end: int32(i)})
+	}
+
+	if last := len(runs) - 1; last > 0 && runs[last].strip == runs[0].strip {
+		runs[0].start = runs[last].start
+		runs = runs[:last]
+	}
+	return runs
+}
+
+// Per-point lookup of the zones beside each edge of a track
+type surfaceMap struct {
+	left, right [][]*pb.SurfaceZone
+	properties  map[pb.Surface]*pb.SurfaceProperties
+}
+
+func newSurfaceMap(track *pb.TrackInfo) *surfaceMap {
+	n := len(track.LeftBoundary)
+	m := &surfaceMap{
+		left:       make([][]*pb.SurfaceZone, n),
+		right:      make([][]*pb.SurfaceZone, n),
+		properties: make(map[pb.Surface]*pb.SurfaceProperties),
+	}
+
+	for _, zone := range track.Zones {
+		if zone.Start < 0 || zone.End < 0 || int(zone.Start) >= n || int(zone.End) >= n {
+			continue
+		}
+
+		side := m.right
+		if zone.Left {
+			side = m.left
+		}
+		for i := zone.Start; ; i = (i + 1) % int32(n) {
+			side[i] = append(side[i], zone)
+			if i == zone.End {
+				break
+			}
+		}
+	}
+
+	properties := track.Surfaces
+	if len(properties) == 0 {
+		properties = surfaceProperties()
+	}
+	for _, surface := range properties {
+		m.properties[surface.Surface] = surface
+	}
+	return m
+}
+
+// Surface under a car located on the track, excess metres beyond its edge
+func (m *surfaceMap) surfaceAt(loc trackLocation, excess float32) pb.Surface {
+	if excess <= 0 {
+		return pb.Surface_SURFACE_ASPHALT
+	}
+
+	side := m.right
+	if loc.lateral >= 0 {
+		side = m.left
+	}
+
+	// Zones belong to boundary points, take the nearer end of the segment
+	point := loc.segment
+	if loc.t > 0.5 {
+		point = (point + 1) % int32(len(side))
+	}
+	for _, zone := range side[point] {
+		if excess >= zone.Inner && excess < zone.Outer {
+			return zone.Surface
+		}
+	}
+	return pb.Surface_SURFACE_GRASS
+}
+
+// Friction and drag of a surface, asphalt for one the track does not list
+func (m *surfaceMap) handling(surface pb.Surface) (friction, drag float32) {
+	if properties, ok := m.properties[surface]; ok {
+		return properties.Friction, properties.Drag
+	}
+	return 1, 0
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	pb "server/proto"
+)
+
+// Sector a track progress value falls in
+func (c *RaceConfig) sectorAt(progress float32) int32 {
+	n := c.setup.sectors()
+	return min(int32(progress*float32(n)), n-1)
+}
+
+// Move the car into the next sector once it gets there
+func (st *RaceState) updateSector(state *CarStateExtended, progress float32) {
+	if st.config.sectorAt(progress) == state.sector+1 {
+		st.completeSector(state)
+		state.sector++
+	}
+}
+
+// Time the sector the car is leaving
+func (st *RaceState) completeSector(state *CarStateExtended) {
+	sectorTime := float32(st.gameTick-state.sectorStartTick) * fixedDt
+	state.sectorStartTick = st.gameTick
+
+	// Splits after a skipped sector mean nothing
+	i := int(state.sector)
+	if len(state.sectorTimes) != i {
+		return
+	}
+	state.sectorTimes = append(state.sectorTimes, sectorTime)
+
+	// The out-lap and invalidated laps set no bests
+	outLap := isTimedSession(st.config.setup.RaceType) && state.Lap == 0
+	best := state.bestSectorTimes[i]
+	if state.lapValid && !outLap && (best == 0 || sectorTime < best) {
+		state.bestSectorTimes[i] = sectorTime
+	}
+}
+
+// Lap and sector times of every car, with the overall bests
+func (st *RaceState) timingInfo() *pb.TimingInfo {
+	n := st.config.setup.sectors()
+	info := &pb.TimingInfo{
+		Sectors:          n,
+		BestSectorTimes:  make([]float32, n),
+		BestSectorCarIds: make([]string, n),
+		Checkpoints:      st.config.checkpointProgress(),
+	}
+	for i := range n {
+		info.SectorEnds = append(info.SectorEnds, float32(i+1)/float32(n))
+	}
+
+	// Overall bests, ties go to the car earlier on the grid
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		if state.bestLapTime > 0 && (info.BestLapTime == 0 || state.bestLapTime < info.BestLapTime) {
+			info.BestLapTime = state.bestLapTime
+			info.BestLapCarId = car.carId
+		}
+		for i, sectorTime := range state.bestSectorTimes {
+			if sectorTime > 0 && (info.BestSectorTimes[i] == 0 || sectorTime < info.BestSectorTimes[i]) {
+				info.BestSectorTimes[i] = sectorTime
+				info.BestSectorCarIds[i] = car.carId
+			}
+		}
+	}
+
+	for _, car := range st.config.carInfos {
+		state := st.carStates[car.carId]
+		timing := &pb.CarTiming{
+			CarId:             car.carId,
+			CurrentSector:     state.sector,
+			BestLapTime:       state.bestLapTime,
+			LapTimes:          append([]float32(nil), state.lapTimes...),
+			SectorTimes:       append([]float32(nil), state.sectorTimes...),
+			LastSectorTimes:   append([]float32(nil), state.lastSectorTimes...),
+			BestSectorTimes:   append([]float32(nil), state.bestSectorTimes...),
+			LapValid:          state.lapValid,
+			NextCheckpoint:    state.checkpoints % st.config.setup.checkpoints(),
+			MissedCheckpoints: state.missedCheckpoints,
+		}
+		if onTrack(state) {
+			timing.CurrentLapTime = float32(st.gameTick-state.lapStartTick) * fixedDt
+		}
+		if len(state.lapTimes) > 0 {
+			timing.LastLapTime = state.lapTimes[len(state.lapTimes)-1]
+		}
+		if state.bestLapTime > 0 {
+			timing.GapToBestLap = state.bestLapTime - info.BestLapTime
+		}
+		info.Cars = append(info.Cars, timing)
+	}
+
+	return info
+}
+
+// Record the tick the car first passed each track point it has reached.
+// Ground covered again after going backwards is not recorded twice.
+func (st *RaceState) updateTimingLine(state *CarStateExtended, point int32) {
+	n := int32(len(st.config.centerline))
+
+	if state.covered < 0 {
+		// Just over the start line
+		state.distance = point
+	} else {
+		// Signed step, so driving backwards takes distance off
+		step := (point - state.lastPoint + n) % n
+		if step > n/2 {
+			step -= n
+		}
+		state.distance += step
+	}
+	state.lastPoint = point
+
+	for d := state.covered + 1; d <= state.distance; d++ {
+		lap := int(d / n)
+		for len(state.timingLine) <= lap {
+			state.timingLine = append(state.timingLine, make([]int32, n))
+		}
+		state.timingLine[lap][d%n] = st.gameTick
+	}
+	state.covered = max(state.covered, state.distance)
+}
+
+// Tick the car passed the given distance, 0 if it has not
+func (state *CarStateExtended) passedAt(distance int32) int32 {
+	if distance < 0 || distance > state.covered || len(state.timingLine) == 0 {
+		return 0
+	}
+	n := int32(len(state.timingLine[0]))
+	return state.timingLine[distance/n][distance%n]
+}
+
+// Seconds the car is behind the car ahead of it: how long ago the car ahead
+// passed the point the car has got to, or had got to when it took the flag.
+// Lapped cars are compared with the car ahead's earlier laps, so the gap stays
+// correct across lapping.
+func (st *RaceState) timeGap(state, ahead *CarStateExtended) float32 {
+	now := st.gameTick
+	if state.finishTick > 0 {
+		now = state.finishTick
+	}
+
+	aheadPassed := ahead.passedAt(state.distance)
+	if aheadPassed == 0 {
+		return 0
+	}
+	// Penalty time added at the flag counts towards the gap
+	return float32(now-aheadPassed)*fixedDt + float32(state.timeAdded-ahead.timeAdded)/1000
+}
@@ -2,209 +2,177 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
-	"log"
 	"math"
 	"os"
+	"path/filepath"
 	pb "server/proto"
-	"sort"
-	"strconv"
+	"server/trackdata"
+	"strings"
 )
 
-func loadTrackFromCSV(filename string) (*pb.TrackInfo, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
+// The track a setup races on, generated or from its file
+func setupTrack(setup raceSetup) (*pb.TrackInfo, error) {
+	if setup.Generator == nil {
+		return loadTrack(setup.TrackFile)
 	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	reader.Comment = '#'
 
-	// Read all records
-	records, err := reader.ReadAll()
+	data, err := trackdata.Generate(setup.generator())
 	if err != nil {
 		return nil, err
 	}
+	return trackInfo(data), nil
+}
 
-	// Parse track points
-	trackPoints := make([]TrackPoint, 0, len(records))
-	for _, record := range records {
-		if len(record) < 4 {
-			continue
-		}
+// Generator settings of a setup, a zero seed following the race seed
+func (setup raceSetup) generator() trackdata.Generator {
+	generator := *setup.Generator
+	if generator.Seed == 0 {
+		generator.Seed = setup.Seed
+	}
+	return generator
+}
 
-		centerX, _ := strconv.ParseFloat(record[0], 32)
-		centerY, _ := strconv.ParseFloat(record[1], 32)
-		widthRight, _ := strconv.ParseFloat(record[2], 32)
-		widthLeft, _ := strconv.ParseFloat(record[3], 32)
+// Whether two setups race on the same track
+func (setup raceSetup) sameTrack(other raceSetup) bool {
+	if (setup.Generator == nil) != (other.Generator == nil) {
+		return false
+	}
+	if setup.Generator != nil {
+		return setup.generator() == other.generator()
+	}
+	return setup.TrackFile == other.TrackFile
+}
 
-		trackPoints = append(trackPoints, TrackPoint{
-			centerX:    float32(centerX),
-			centerY:    float32(centerY),
-			widthLeft:  float32(widthLeft),
-			widthRight: float32(widthRight),
-		})
+// Track info for a prepared track, run-off worked out from its shape
+func trackInfo(data *trackdata.Track) *pb.TrackInfo {
+	leftBoundary, rightBoundary := data.Boundaries()
+	return &pb.TrackInfo{
+		TrackId:       data.Meta.ID,
+		Name:          data.Meta.Name,
+		LeftBoundary:  leftBoundary,
+		RightBoundary: rightBoundary,
+		Zones:         surfaceZones(data.Curvature(curvatureSpan)),
+		Surfaces:      surfaceProperties(),
 	}
+}
 
-	if len(trackPoints) == 0 {
-		log.Fatal("No track points loaded")
+// Load, check and prepare a track and its pit lane, from a file in any
+// format trackdata reads
+func loadTrack(filename string) (*pb.TrackInfo, error) {
+	data, err := trackdata.Load(filename, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := data.Prepare(); err != nil {
+		return nil, err
 	}
 
-	// Generate left and right boundaries
-	leftBoundary := make([]*pb.Point3D, len(trackPoints))
-	rightBoundary := make([]*pb.Point3D, len(trackPoints))
-
-	for i := 0; i < len(trackPoints); i++ {
-		point := trackPoints[i]
-
-		// Calculate tangent vector (direction of track)
-		var dx, dy float32
-		if i == 0 {
-			// Use direction to next point
-			dx = trackPoints[i+1].centerX - point.centerX
-			dy = trackPoints[i+1].centerY - point.centerY
-		} else if i == len(trackPoints)-1 {
-			// Use direction from previous point
-			dx = point.centerX - trackPoints[i-1].centerX
-			dy = point.centerY - trackPoints[i-1].centerY
-		} else {
-			// Average direction
-			dx = trackPoints[i+1].centerX - trackPoints[i-1].centerX
-			dy = trackPoints[i+1].centerY - trackPoints[i-1].centerY
-		}
+	track := trackInfo(data)
 
-		// Normalize tangent
-		length := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-		if length > 0 {
-			dx /= length
-			dy /= length
+	// The pit lane, if there is one, sits next to the track as name.pit.csv
+	pitFile := pitLanePath(filename)
+	if _, err := os.Stat(pitFile); err == nil {
+		lane, err := trackdata.Load(pitFile, false)
+		if err != nil {
+			return nil, err
 		}
-
-		// Perpendicular vector (rotated 90 degrees)
-		// Left is (-dy, dx), Right is (dy, -dx)
-		perpX := -dy
-		perpY := dx
-
-		// Calculate boundary points
-		leftBoundary[i] = &pb.Point3D{
-			X: point.centerX + perpX*point.widthLeft,
-			Y: point.centerY + perpY*point.widthLeft,
-			Z: 0,
+		if err := lane.Prepare(); err != nil {
+			return nil, err
 		}
 
-		rightBoundary[i] = &pb.Point3D{
-			X: point.centerX - perpX*point.widthRight,
-			Y: point.centerY - perpY*point.widthRight,
-			Z: 0,
+		pitLeft, pitRight := lane.Boundaries()
+		track.PitLane = &pb.PitLane{
+			LeftBoundary:  pitLeft,
+			RightBoundary: pitRight,
+			SpeedLimit:    pitSpeedLimit,
+			LimiterStart:  pitLimiterMargin,
+			LimiterEnd:    lane.Length() - pitLimiterMargin,
 		}
 	}
 
-	return &pb.TrackInfo{
-		TrackId:       "hockenheim",
-		Name:          "Hockenheim Circuit",
-		LeftBoundary:  leftBoundary,
-		RightBoundary: rightBoundary,
-	}, nil
+	return track, nil
+}
+
+// Pit lane file that goes with a track file
+func pitLanePath(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + pitLaneSuffix
 }
 
 // GetTrack RPC - returns track information without authentication
-func (s *CarServer) GetTrack(ctx context.Context, req *pb.Empty) (*pb.TrackInfo, error) {
+func (c *CarServer) GetTrack(ctx context.Context, req *pb.SessionRequest) (*pb.TrackInfo, error) {
+	s, err := c.session(req.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.track, nil
+	return s.config.track, nil
 }
 
-// Calculate progress along track (0 to 1)
-func (s *CarServer) calculateTrackProgress(pos *pb.Point3D) float32 {
-	// Simple implementation: find closest track point
-	minDist := float32(math.MaxFloat32)
-	closestIdx := 0
-
-	for i, leftPoint := range s.track.LeftBoundary {
-		rightPoint := s.track.RightBoundary[i]
-		centerX := (leftPoint.X + rightPoint.X) / 2
-		centerY := (leftPoint.Y + rightPoint.Y) / 2
+// Rebuild the centerline and track widths from the boundary pairs
+func buildCenterline(track *pb.TrackInfo) []TrackPoint {
+	return centerlineBetween(track.LeftBoundary, track.RightBoundary)
+}
 
-		dx := pos.X - centerX
-		dy := pos.Y - centerY
-		dist := dx*dx + dy*dy
+// Centerline and widths halfway between two boundaries
+func centerlineBetween(leftBoundary, rightBoundary []*pb.Point3D) []TrackPoint {
+	points := make([]TrackPoint, len(leftBoundary))
+	for i, left := range leftBoundary {
+		right := rightBoundary[i]
+		centerX := (left.X + right.X) / 2
+		centerY := (left.Y + right.Y) / 2
+		centerZ := (left.Z + right.Z) / 2
+		halfWidth := float32(math.Hypot(float64(left.X-right.X), float64(left.Y-right.Y))) / 2
+
+		banking := float32(0)
+		if halfWidth > 0 {
+			banking = float32(math.Atan2(float64(left.Z-right.Z), float64(2*halfWidth)) * 180 / math.Pi)
+		}
 
-		if dist < minDist {
-			minDist = dist
-			closestIdx = i
+		points[i] = TrackPoint{
+			centerX:    centerX,
+			centerY:    centerY,
+			centerZ:    centerZ,
+			widthLeft:  halfWidth,
+			widthRight: halfWidth,
+			banking:    banking,
 		}
 	}
-
-	return float32(closestIdx) / float32(len(s.track.LeftBoundary))
+	return points
 }
 
-// Calculate intervals between cars
-func (s *CarServer) calculateIntervals() ([]*pb.CarInterval, []*pb.CarInterval) {
-	// Sort cars by position (lap then progress)
-	type carPosition struct {
-		carId    string
-		lap      int32
-		progress float32
+// Calculate intervals between cars, in seconds
+func (st *RaceState) calculateIntervals() ([]*pb.CarInterval, []*pb.CarInterval) {
+	// Timed sessions are ranked by best lap instead
+	if isTimedSession(st.config.setup.RaceType) {
+		return st.sessionIntervals()
 	}
 
-	positions := make([]carPosition, 0, len(s.carStates))
-	for carId, state := range s.carStates {
-		progress := s.calculateTrackProgress(state.Position)
-		positions = append(positions, carPosition{
-			carId:    carId,
-			lap:      state.Lap,
-			progress: progress,
-		})
-	}
+	order := st.raceOrder()
+	toLeader := make([]*pb.CarInterval, 0, len(order))
+	forPosition := make([]*pb.CarInterval, 0, len(order))
 
-	// Sort by lap (descending) then progress (descending)
-	sort.Slice(positions, func(i, j int) bool {
-		if positions[i].lap != positions[j].lap {
-			return positions[i].lap > positions[j].lap
+	for i, carId := range order {
+		state := st.carStates[carId]
+		intervalToLeader := float32(0)
+		intervalForPosition := float32(0)
+		if i > 0 {
+			intervalToLeader = st.timeGap(state, st.carStates[order[0]])
+			intervalForPosition = st.timeGap(state, st.carStates[order[i-1]])
 		}
-		return positions[i].progress > positions[j].progress
-	})
-
-	// Calculate intervals to leader
-	toLeader := make([]*pb.CarInterval, 0, len(positions))
-	forPosition := make([]*pb.CarInterval, 0, len(positions))
-
-	if len(positions) == 0 {
-		return toLeader, forPosition
-	}
-
-	leaderLap := positions[0].lap
-	leaderProgress := positions[0].progress
-
-	for i, pos := range positions {
-		// Interval to leader (in laps + progress)
-		lapDiff := float32(leaderLap - pos.lap)
-		progressDiff := leaderProgress - pos.progress
-		intervalToLeader := lapDiff + progressDiff
 
 		toLeader = append(toLeader, &pb.CarInterval{
-			CarId:    pos.carId,
+			CarId:    carId,
 			Position: int32(i + 1),
-			Laps:     pos.lap,
+			Laps:     state.Lap,
 			Interval: intervalToLeader,
 		})
-
-		// Interval to car ahead
-		intervalForPosition := float32(0.0)
-		if i > 0 {
-			prevLap := positions[i-1].lap
-			prevProgress := positions[i-1].progress
-			lapDiff := float32(prevLap - pos.lap)
-			progressDiff := prevProgress - pos.progress
-			intervalForPosition = lapDiff + progressDiff
-		}
-
 		forPosition = append(forPosition, &pb.CarInterval{
-			CarId:    pos.carId,
+			CarId:    carId,
 			Position: int32(i + 1),
-			Laps:     pos.lap,
+			Laps:     state.Lap,
 			Interval: intervalForPosition,
 		})
 	}
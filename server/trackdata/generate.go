@@ -0,0 +1,133 @@
+package trackdata
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Generated layouts are checked like any other track and drawn again, each
+// time a little tamer, until one passes
+const (
+	generateAttempts  = 50
+	generateSteps     = 24         // centerline points per corner before resampling
+	generateSmoothing = 2          // passes
+	generateStream    = 0x7261636b // PCG stream, so layouts do not follow race randomness
+)
+
+// Settings for a procedurally generated track. Zero length, corners and
+// width take the defaults, as do a negative width variation and difficulty,
+// for which zero is a setting of its own.
+type Generator struct {
+	Seed           uint64  `json:"seed"`
+	Length         float32 `json:"length,omitempty"`          // metres, default 4000
+	Corners        int     `json:"corners,omitempty"`         // default 12
+	Width          float32 `json:"width,omitempty"`           // metres edge to edge on average, default 12
+	WidthVariation float32 `json:"width_variation,omitempty"` // fraction either way of the average, 0 for constant width, default 0.2
+	Difficulty     float32 `json:"difficulty,omitempty"`      // 0 flowing to 1 tight and twisty, default 0.5
+}
+
+// Settings with the defaults filled in and the rest held in range
+func (g Generator) withDefaults() Generator {
+	if g.Length <= 0 {
+		g.Length = 4000
+	}
+	if g.Corners <= 0 {
+		g.Corners = 12
+	}
+	g.Corners = max(g.Corners, 3)
+	if g.Width <= 0 {
+		g.Width = 12
+	}
+	if g.WidthVariation < 0 {
+		g.WidthVariation = 0.2
+	}
+	g.WidthVariation = min(g.WidthVariation, 0.9)
+	if g.Difficulty < 0 {
+		g.Difficulty = 0.5
+	}
+	g.Difficulty = min(g.Difficulty, 1)
+	return g
+}
+
+// A closed track that does not cross itself, the same for the same settings.
+// Corners are control points scattered around a circle, further from it the
+// harder the track, joined by a smooth curve through them.
+func Generate(g Generator) (*Track, error) {
+	g = g.withDefaults()
+	rng := rand.New(rand.NewPCG(g.Seed, generateStream))
+
+	var err error
+	for attempt := range generateAttempts {
+		track := g.layout(rng, 1-float64(attempt)/generateAttempts)
+		if err = track.Prepare(); err == nil {
+			return track, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid layout for seed %d in %d attempts: %v", g.Seed, generateAttempts, err)
+}
+
+// One random layout, not yet checked, with the scatter of the corners scaled
+// by wildness
+func (g Generator) layout(rng *rand.Rand, wildness float64) *Track {
+	n := g.Corners
+	spread := 2 * math.Pi / float64(n)
+
+	// Control points in order round the origin, so the polygon through them
+	// never crosses itself
+	xs, ys, widths := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range n {
+		scatter := wildness * float64(g.Difficulty)
+		angle := (float64(i) + (0.3+0.5*scatter)*(rng.Float64()-0.5)) * spread
+		radius := 1 + 0.6*scatter*(2*rng.Float64()-1)
+		xs[i], ys[i] = radius*math.Cos(angle), radius*math.Sin(angle)
+		widths[i] = float64(g.Width) * (1 + float64(g.WidthVariation)*(2*rng.Float64()-1))
+	}
+
+	// Catmull-Rom spline through the control points
+	var points []Point
+	for i := range n {
+		p0, p1, p2, p3 := (i-1+n)%n, i, (i+1)%n, (i+2)%n
+		for step := range generateSteps {
+			t := float64(step) / generateSteps
+			t2, t3 := t*t, t*t*t
+			spline := func(v []float64) float64 {
+				return 0.5 * (2*v[p1] + (v[p2]-v[p0])*t +
+					(2*v[p0]-5*v[p1]+4*v[p2]-v[p3])*t2 +
+					(3*v[p1]-v[p0]-3*v[p2]+v[p3])*t3)
+			}
+			width := widths[p1] + (widths[p2]-widths[p1])*t
+			points = append(points, Point{
+				X:          float32(spline(xs)),
+				Y:          float32(spline(ys)),
+				WidthLeft:  float32(width / 2),
+				WidthRight: float32(width / 2),
+			})
+		}
+	}
+
+	track := &Track{
+		Meta: Meta{
+			ID:        fmt.Sprintf("generated-%d", g.Seed),
+			Name:      fmt.Sprintf("Generated %d", g.Seed),
+			Spacing:   importSpacing,
+			Smoothing: generateSmoothing,
+		},
+		Points: points,
+		Closed: true,
+		File:   fmt.Sprintf("generated track %d", g.Seed),
+	}
+
+	// Grow the unit-sized layout to the length asked for, widths aside
+	scale := g.Length / track.Length()
+	for i := range track.Points {
+		track.Points[i].X *= scale
+		track.Points[i].Y *= scale
+	}
+
+	// Half the layouts run clockwise
+	if rng.IntN(2) == 1 {
+		track.Reverse()
+	}
+	return track
+}
@@ -0,0 +1,57 @@
+package trackdata
+
+import "testing"
+
+func TestGeneratorDefaults(t *testing.T) {
+	tests := []struct {
+		name                       string
+		in                         Generator
+		widthVariation, difficulty float32
+	}{
+		{"unset", Generator{WidthVariation: -1, Difficulty: -1}, 0.2, 0.5},
+		{"zero", Generator{}, 0, 0},
+		{"set", Generator{WidthVariation: 0.1, Difficulty: 0.8}, 0.1, 0.8},
+		{"out of range", Generator{WidthVariation: 5, Difficulty: 5}, 0.9, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := test.in.withDefaults()
+			if g.WidthVariation != test.widthVariation || g.Difficulty != test.difficulty {
+				t.Errorf("width variation %v and difficulty %v, want %v and %v",
+					g.WidthVariation, g.Difficulty, test.widthVariation, test.difficulty)
+			}
+		})
+	}
+}
+
+func TestGenerateConstantWidth(t *testing.T) {
+	track, err := Generate(Generator{Seed: 7, Width: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range track.Points {
+		if width := p.WidthLeft + p.WidthRight; width < 9.99 || width > 10.01 {
+			t.Fatalf("point %d is %v m wide, want 10", i, width)
+		}
+	}
+}
+
+func TestGenerateRepeatable(t *testing.T) {
+	a, err := Generate(Generator{Seed: 42, WidthVariation: -1, Difficulty: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate(Generator{Seed: 42, WidthVariation: -1, Difficulty: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Points) != len(b.Points) {
+		t.Fatalf("%d points then %d", len(a.Points), len(b.Points))
+	}
+	for i := range a.Points {
+		if a.Points[i] != b.Points[i] {
+			t.Fatalf("point %d differs: %v and %v", i, a.Points[i], b.Points[i])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package trackdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The parts of a GeoJSON object an import needs: a FeatureCollection, a
+// Feature or a bare geometry
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Features    []geoJSON       `json:"features"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Properties  map[string]any  `json:"properties"`
+}
+
+// Read a track from the first LineString in a GeoJSON file. Coordinates are
+// longitude and latitude, as GeoJSON has them, unless the feature's "units"
+// property is "m". Other properties set the metadata, and "width",
+// "width_left" and "width_right" the widths, each a number for the whole
+// track or an array with one per point.
+func ReadGeoJSON(r io.Reader, filename string, closed bool) (*Track, error) {
+	var root geoJSON
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	feature := findLineString(&root, nil)
+	if feature == nil {
+		return nil, fmt.Errorf("%s: no LineString", filename)
+	}
+	var coords [][]float64
+	if err := json.Unmarshal(feature.Coordinates, &coords); err != nil {
+		return nil, fmt.Errorf("%s: LineString coordinates: %v", filename, err)
+	}
+	props := feature.Properties
+
+	line := importedLine{defaultLeft: importWidth / 2, defaultRight: importWidth / 2}
+	for i, coord := range coords {
+		if len(coord) < 2 {
+			return nil, fmt.Errorf("%s: coordinate %d has %d values", filename, i, len(coord))
+		}
+	}
+	if units, _ := props["units"].(string); units == "m" || units == "metres" || units == "meters" {
+		for _, coord := range coords {
+			line.xs = append(line.xs, float32(coord[0]))
+			line.ys = append(line.ys, float32(coord[1]))
+		}
+	} else {
+		lons := make([]float64, len(coords))
+		lats := make([]float64, len(coords))
+		for i, coord := range coords {
+			lons[i], lats[i] = coord[0], coord[1]
+		}
+		line.xs, line.ys = project(lons, lats)
+	}
+	if len(coords) > 0 && len(coords[0]) > 2 {
+		line.zs = make([]float32, len(coords))
+		for i, coord := range coords {
+			if len(coord) > 2 {
+				line.zs[i] = float32(coord[2])
+			}
+		}
+	}
+
+	// Widths, a total split evenly or one per side
+	var err error
+	if width, ok := props["width"]; ok {
+		var total []float32
+		if total, err = widthProperty(width, len(coords)); err != nil {
+			return nil, fmt.Errorf("%s: width: %v", filename, err)
+		}
+		line.widthLeft = make([]float32, len(coords))
+		line.widthRight = make([]float32, len(coords))
+		for i, w := range total {
+			line.widthLeft[i], line.widthRight[i] = w/2, w/2
+		}
+	}
+	if width, ok := props["width_left"]; ok {
+		if line.widthLeft, err = widthProperty(width, len(coords)); err != nil {
+			return nil, fmt.Errorf("%s: width_left: %v", filename, err)
+		}
+	}
+	if width, ok := props["width_right"]; ok {
+		if line.widthRight, err = widthProperty(width, len(coords)); err != nil {
+			return nil, fmt.Errorf("%s: width_right: %v", filename, err)
+		}
+	}
+
+	meta := defaultMeta(filename)
+	for key, value := range props {
+		if s, ok := value.(string); ok {
+			err = meta.set(key, s)
+		} else if _, ok := value.([]any); !ok {
+			err = meta.set(key, strings.TrimSpace(fmt.Sprint(value)))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	return line.track(meta, filename, closed)
+}
+
+// First LineString in an object, with the properties of the feature that
+// holds it
+func findLineString(object *geoJSON, props map[string]any) *geoJSON {
+	if object.Properties != nil {
+		props = object.Properties
+	}
+
+	switch object.Type {
+	case "LineString":
+		if props == nil {
+			props = map[string]any{}
+		}
+		return &geoJSON{Coordinates: object.Coordinates, Properties: props}
+	case "Feature":
+		if object.Geometry != nil {
+			return findLineString(object.Geometry, props)
+		}
+	case "FeatureCollection":
+		for i := range object.Features {
+			if found := findLineString(&object.Features[i], nil); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// A width property, one number for every point or an array of them
+func widthProperty(value any, n int) ([]float32, error) {
+	widths := make([]float32, n)
+	switch value := value.(type) {
+	case float64:
+		for i := range widths {
+			widths[i] = float32(value)
+		}
+	case []any:
+		if len(value) != n {
+			return nil, fmt.Errorf("%d widths for %d points", len(value), n)
+		}
+		for i, v := range value {
+			w, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("width %d is not a number", i)
+			}
+			widths[i] = float32(w)
+		}
+	default:
+		return nil, fmt.Errorf("not a number or an array of numbers")
+	}
+	return widths, nil
+}
@@ -0,0 +1,79 @@
+package trackdata
+
+import (
+	"fmt"
+	"math"
+)
+
+// Imported centerlines are drawn by hand or mapped, with points wherever the
+// author clicked. Unless the source says otherwise they get this width and
+// are resampled and smoothed to round off the corners between them.
+const (
+	importWidth     = float32(12.0) // metres, edge to edge
+	importSpacing   = float32(5.0)  // metres between points
+	importSmoothing = 8             // passes
+	earthRadius     = 6371008.8     // metres, mean
+)
+
+// A centerline in metres with widths either side, as an importer finds it
+type importedLine struct {
+	xs, ys, zs                []float32
+	widthLeft                 []float32 // per point, or nil for the same everywhere
+	widthRight                []float32
+	defaultLeft, defaultRight float32
+}
+
+// Track from an imported centerline, metadata defaults filled in
+func (line importedLine) track(meta Meta, filename string, closed bool) (*Track, error) {
+	if len(line.xs) < 2 {
+		return nil, fmt.Errorf("%s: centerline needs at least 2 points, has %d", filename, len(line.xs))
+	}
+	if meta.Spacing == 0 {
+		meta.Spacing = importSpacing
+	}
+	if meta.Smoothing == 0 {
+		meta.Smoothing = importSmoothing
+	}
+
+	points := make([]Point, len(line.xs))
+	for i := range points {
+		points[i] = Point{
+			X:          line.xs[i],
+			Y:          line.ys[i],
+			WidthLeft:  line.defaultLeft,
+			WidthRight: line.defaultRight,
+		}
+		if line.zs != nil {
+			points[i].Z = line.zs[i]
+		}
+		if line.widthLeft != nil {
+			points[i].WidthLeft = line.widthLeft[i]
+		}
+		if line.widthRight != nil {
+			points[i].WidthRight = line.widthRight[i]
+		}
+	}
+	return &Track{Meta: meta, Points: points, Closed: closed, File: filename}, nil
+}
+
+// Project longitudes and latitudes in degrees to metres east and north of
+// their middle. Equirectangular, which is plenty over the size of a circuit.
+func project(lons, lats []float64) ([]float32, []float32) {
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	for i := range lons {
+		minLon, maxLon = min(minLon, lons[i]), max(maxLon, lons[i])
+		minLat, maxLat = min(minLat, lats[i]), max(maxLat, lats[i])
+	}
+	lon0, lat0 := (minLon+maxLon)/2, (minLat+maxLat)/2
+	scaleX := earthRadius * math.Cos(lat0*math.Pi/180) * math.Pi / 180
+	scaleY := earthRadius * math.Pi / 180
+
+	xs := make([]float32, len(lons))
+	ys := make([]float32, len(lats))
+	for i := range lons {
+		xs[i] = float32((lons[i] - lon0) * scaleX)
+		ys[i] = float32((lats[i] - lat0) * scaleY)
+	}
+	return xs, ys
+}
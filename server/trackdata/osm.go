@@ -0,0 +1,101 @@
+package trackdata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The parts of an OpenStreetMap XML file an import needs
+type osmFile struct {
+	Nodes []struct {
+		ID  int64   `xml:"id,attr"`
+		Lat float64 `xml:"lat,attr"`
+		Lon float64 `xml:"lon,attr"`
+	} `xml:"node"`
+	Ways []struct {
+		ID   int64 `xml:"id,attr"`
+		Refs []struct {
+			Ref int64 `xml:"ref,attr"`
+		} `xml:"nd"`
+		Tags []struct {
+			Key   string `xml:"k,attr"`
+			Value string `xml:"v,attr"`
+		} `xml:"tag"`
+	} `xml:"way"`
+}
+
+// Read a track from a highway=raceway way in an OpenStreetMap XML export.
+// A closed way is taken over an open one, and a longer over a shorter one.
+// Longitudes and latitudes are projected to metres, the width tag sets the
+// width and the name tag the name. Tags prefixed "racing:" set the rest of
+// the metadata, as in racing:country=Spain.
+func ReadOSM(r io.Reader, filename string, closed bool) (*Track, error) {
+	var file osmFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	nodes := make(map[int64][2]float64, len(file.Nodes))
+	for _, node := range file.Nodes {
+		nodes[node.ID] = [2]float64{node.Lon, node.Lat}
+	}
+
+	best := -1
+	bestClosed := false
+	for i, way := range file.Ways {
+		raceway := false
+		for _, tag := range way.Tags {
+			raceway = raceway || (tag.Key == "highway" && tag.Value == "raceway")
+		}
+		if !raceway || len(way.Refs) < 2 {
+			continue
+		}
+
+		loop := way.Refs[0].Ref == way.Refs[len(way.Refs)-1].Ref
+		if best < 0 || (loop && !bestClosed) ||
+			(loop == bestClosed && len(way.Refs) > len(file.Ways[best].Refs)) {
+			best, bestClosed = i, loop
+		}
+	}
+	if best < 0 {
+		return nil, fmt.Errorf("%s: no highway=raceway way", filename)
+	}
+	way := file.Ways[best]
+
+	lons := make([]float64, len(way.Refs))
+	lats := make([]float64, len(way.Refs))
+	for i, ref := range way.Refs {
+		node, ok := nodes[ref.Ref]
+		if !ok {
+			return nil, fmt.Errorf("%s: way %d uses node %d, which is not in the file", filename, way.ID, ref.Ref)
+		}
+		lons[i], lats[i] = node[0], node[1]
+	}
+
+	width := importWidth
+	meta := defaultMeta(filename)
+	for _, tag := range way.Tags {
+		switch {
+		case tag.Key == "width":
+			// Widths may carry a unit, only metres are understood
+			v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(tag.Value, "m")), 32)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("%s: way %d: invalid width %q", filename, way.ID, tag.Value)
+			}
+			width = float32(v)
+		case tag.Key == "name":
+			meta.Name = tag.Value
+		case strings.HasPrefix(tag.Key, "racing:"):
+			if err := meta.set(strings.TrimPrefix(tag.Key, "racing:"), tag.Value); err != nil {
+				return nil, fmt.Errorf("%s: way %d: %v", filename, way.ID, err)
+			}
+		}
+	}
+
+	line := importedLine{defaultLeft: width / 2, defaultRight: width / 2}
+	line.xs, line.ys = project(lons, lats)
+	return line.track(meta, filename, closed)
+}
@@ -0,0 +1,205 @@
+package trackdata
+
+import (
+	"fmt"
+	"math"
+
+	pb "server/proto"
+)
+
+// Closing points closer than this to the first point repeat it
+const closingTolerance = float32(0.01) // metres
+
+// The usual steps between reading a file and racing on it: drop a closing
+// point that repeats the first, put the start/finish line first, resample and
+// smooth as the metadata asks, then validate
+func (t *Track) Prepare() error {
+	if t.Closed && len(t.Points) > 1 && distance(t.Points[0], t.Points[len(t.Points)-1]) < closingTolerance {
+		t.Points = t.Points[:len(t.Points)-1]
+	}
+	if err := t.StartAt(t.Meta.Start); err != nil {
+		return err
+	}
+	if t.Meta.Spacing > 0 {
+		t.Resample(t.Meta.Spacing)
+	}
+	t.Smooth(t.Meta.Smoothing)
+	return t.Validate()
+}
+
+// Turn a closed track so point start comes first
+func (t *Track) StartAt(start int) error {
+	if start == 0 {
+		return nil
+	}
+	if !t.Closed {
+		return fmt.Errorf("%s: a pit lane has no start/finish line", t.File)
+	}
+	if start >= len(t.Points) {
+		return fmt.Errorf("%s: start %d beyond the last of %d points", t.File, start, len(t.Points))
+	}
+	t.Points = append(t.Points[start:len(t.Points):len(t.Points)], t.Points[:start]...)
+	return nil
+}
+
+// Replace the points with ones evenly spaced along the centerline, as close
+// to spacing apart as fits a whole number of them in
+func (t *Track) Resample(spacing float32) {
+	length := t.Length()
+	if spacing <= 0 || length == 0 {
+		return
+	}
+
+	count := int(math.Round(float64(length / spacing)))
+	if !t.Closed {
+		count++
+	}
+	count = max(count, 3)
+	step := length / float32(count)
+	if !t.Closed {
+		step = length / float32(count-1)
+	}
+
+	points := make([]Point, 0, count)
+	segment, segmentStart := 0, float32(0)
+	for i := 0; i < count; i++ {
+		arc := float32(i) * step
+
+		// Walk on to the segment holding arc, the last one takes any rounding
+		a, b := t.segment(segment)
+		for arc > segmentStart+distance(a, b) && segment < t.segments()-1 {
+			segmentStart += distance(a, b)
+			segment++
+			a, b = t.segment(segment)
+		}
+
+		s := float32(0)
+		if d := distance(a, b); d > 0 {
+			s = min((arc-segmentStart)/d, 1)
+		}
+		points = append(points, lerp(a, b, s))
+	}
+	t.Points = points
+}
+
+// Point a fraction s of the way from a to b
+func lerp(a, b Point, s float32) Point {
+	return Point{
+		X:          a.X + s*(b.X-a.X),
+		Y:          a.Y + s*(b.Y-a.Y),
+		Z:          a.Z + s*(b.Z-a.Z),
+		WidthLeft:  a.WidthLeft + s*(b.WidthLeft-a.WidthLeft),
+		WidthRight: a.WidthRight + s*(b.WidthRight-a.WidthRight),
+		Banking:    a.Banking + s*(b.Banking-a.Banking),
+		Line:       a.Line,
+	}
+}
+
+// Smooth the centerline with passes of a 1-2-1 filter. The ends of a pit
+// lane stay where they are.
+func (t *Track) Smooth(passes int) {
+	n := len(t.Points)
+	if n < 3 {
+		return
+	}
+
+	for range passes {
+		smoothed := make([]Point, n)
+		copy(smoothed, t.Points)
+		for i := range t.Points {
+			if !t.Closed && (i == 0 || i == n-1) {
+				continue
+			}
+			prev, next := t.Points[(i-1+n)%n], t.Points[(i+1)%n]
+			smoothed[i].X = (prev.X + 2*t.Points[i].X + next.X) / 4
+			smoothed[i].Y = (prev.Y + 2*t.Points[i].Y + next.Y) / 4
+			smoothed[i].Z = (prev.Z + 2*t.Points[i].Z + next.Z) / 4
+		}
+		t.Points = smoothed
+	}
+}
+
+// Unit direction of the centerline at a point. Closed tracks look across the
+// start line for the neighbours of the first and last points, pit lanes use
+// the one segment at either end.
+func (t *Track) Tangent(i int) (float32, float32) {
+	n := len(t.Points)
+	prev, next := i-1, i+1
+	if t.Closed {
+		prev, next = (i-1+n)%n, (i+1)%n
+	} else {
+		prev, next = max(prev, 0), min(next, n-1)
+	}
+
+	dx := t.Points[next].X - t.Points[prev].X
+	dy := t.Points[next].Y - t.Points[prev].Y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return 0, 0
+	}
+	return dx / length, dy / length
+}
+
+// Left and right edges, banking raises the left edge and lowers the right
+func (t *Track) Boundaries() ([]*pb.Point3D, []*pb.Point3D) {
+	left := make([]*pb.Point3D, len(t.Points))
+	right := make([]*pb.Point3D, len(t.Points))
+	for i, point := range t.Points {
+		// Left is the tangent turned 90 degrees anticlockwise
+		dx, dy := t.Tangent(i)
+		perpX, perpY := -dy, dx
+
+		bankRise := float32(math.Tan(float64(point.Banking) * math.Pi / 180))
+		left[i] = &pb.Point3D{
+			X: point.X + perpX*point.WidthLeft,
+			Y: point.Y + perpY*point.WidthLeft,
+			Z: point.Z + bankRise*point.WidthLeft,
+		}
+		right[i] = &pb.Point3D{
+			X: point.X - perpX*point.WidthRight,
+			Y: point.Y - perpY*point.WidthRight,
+			Z: point.Z - bankRise*point.WidthRight,
+		}
+	}
+	return left, right
+}
+
+// Signed curvature at each point in 1/m, positive turning left, measured over
+// the circle through the neighbours span points away
+func (t *Track) Curvature(span int) []float32 {
+	n := len(t.Points)
+	curvature := make([]float32, n)
+	for i := range t.Points {
+		prev, next := i-span, i+span
+		if t.Closed {
+			prev, next = (prev%n+n)%n, next%n
+		} else if prev < 0 || next >= n {
+			continue
+		}
+		a, b, c := t.Points[prev], t.Points[i], t.Points[next]
+
+		abX, abY := float64(b.X-a.X), float64(b.Y-a.Y)
+		bcX, bcY := float64(c.X-b.X), float64(c.Y-b.Y)
+		acX, acY := float64(c.X-a.X), float64(c.Y-a.Y)
+
+		lengths := math.Hypot(abX, abY) * math.Hypot(bcX, bcY) * math.Hypot(acX, acY)
+		if lengths == 0 {
+			continue
+		}
+		curvature[i] = float32(2 * (abX*bcY - abY*bcX) / lengths)
+	}
+	return curvature
+}
+
+// Which way a closed track runs, from the sign of the area it encloses
+func (t *Track) Direction() pb.TrackDirection {
+	area := float32(0)
+	for i := range t.Points {
+		a, b := t.segment(i)
+		area += a.X*b.Y - b.X*a.Y
+	}
+	if area > 0 {
+		return pb.TrackDirection_DIRECTION_COUNTERCLOCKWISE
+	}
+	return pb.TrackDirection_DIRECTION_CLOCKWISE
+}
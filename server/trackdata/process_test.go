@@ -0,0 +1,69 @@
+package trackdata
+
+import "testing"
+
+func TestResample(t *testing.T) {
+	square := []Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}}
+	line := []Point{{X: 0, WidthLeft: 2, Line: 3}, {X: 100, WidthLeft: 4, Line: 4}}
+
+	tests := []struct {
+		name      string
+		points    []Point
+		closed    bool
+		spacing   float32
+		wantCount int
+		wantStep  float32
+	}{
+		{"closed", square, true, 10, 40, 10},
+		{"closed, rounded", square, true, 11, 36, 400.0 / 36},
+		{"open keeps both ends", line, false, 30, 4, 100.0 / 3},
+		{"open", line, false, 10, 11, 10},
+		{"no spacing", square, true, 0, 4, 100},
+		{"at least three points", line, false, 500, 3, 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			track := &Track{Points: append([]Point(nil), test.points...), Closed: test.closed}
+			length := track.Length()
+			track.Resample(test.spacing)
+
+			if len(track.Points) != test.wantCount {
+				t.Fatalf("%d points, want %d", len(track.Points), test.wantCount)
+			}
+			first, last := track.Points[0], track.Points[len(track.Points)-1]
+			if !test.closed && (first.X != test.points[0].X || last.X != test.points[len(test.points)-1].X) {
+				t.Errorf("ends at %v and %v, want the lane's", first.X, last.X)
+			}
+			if got := track.Length(); got < length-0.01 || got > length+0.01 {
+				t.Errorf("length %v after resampling, want %v", got, length)
+			}
+			for i := 0; i < track.segments(); i++ {
+				if d := distance(track.segment(i)); d < test.wantStep-0.01 || d > test.wantStep+0.01 {
+					t.Fatalf("segment %d is %v m, want %v", i, d, test.wantStep)
+				}
+			}
+		})
+	}
+}
+
+func TestResampleInterpolates(t *testing.T) {
+	track := &Track{Points: []Point{{X: 0, WidthLeft: 2, Line: 3}, {X: 100, WidthLeft: 4, Line: 4}}}
+	track.Resample(25)
+
+	want := []Point{
+		{X: 0, WidthLeft: 2, Line: 3},
+		{X: 25, WidthLeft: 2.5, Line: 3},
+		{X: 50, WidthLeft: 3, Line: 3},
+		{X: 75, WidthLeft: 3.5, Line: 3},
+		{X: 100, WidthLeft: 4, Line: 3},
+	}
+	if len(track.Points) != len(want) {
+		t.Fatalf("%d points, want %d", len(track.Points), len(want))
+	}
+	for i, p := range track.Points {
+		if p != want[i] {
+			t.Errorf("point %d %+v, want %+v", i, p, want[i])
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package trackdata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Column order of files without a header row
+var defaultColumns = []string{"x", "y", "wide_right", "wide_left", "z", "banking"}
+
+// Header names for each column, ours and the TUM race track database's
+var columnNames = map[string]string{
+	"x":            "x",
+	"x_m":          "x",
+	"y":            "y",
+	"y_m":          "y",
+	"z":            "z",
+	"z_m":          "z",
+	"wide_right":   "wide_right",
+	"w_tr_right_m": "wide_right",
+	"wide_left":    "wide_left",
+	"w_tr_left_m":  "wide_left",
+	"banking":      "banking",
+}
+
+// Columns every file needs
+var requiredColumns = []string{"x", "y", "wide_right", "wide_left"}
+
+// File extensions Load reads
+var Extensions = []string{".csv", ".geojson", ".svg", ".osm"}
+
+// Read a track or pit lane file in any of the formats, by its extension
+func Load(filename string, closed bool) (*Track, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".geojson":
+		return ReadGeoJSON(file, filename, closed)
+	case ".svg":
+		return ReadSVG(file, filename, closed)
+	case ".osm":
+		return ReadOSM(file, filename, closed)
+	}
+	return Read(file, filename, closed)
+}
+
+// Read only the metadata of a track file
+func LoadMeta(filename string) (Meta, error) {
+	if !strings.EqualFold(filepath.Ext(filename), ".csv") {
+		track, err := Load(filename, true)
+		if err != nil {
+			return Meta{}, err
+		}
+		return track.Meta, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return Meta{}, err
+	}
+	defer file.Close()
+
+	return readMeta(file, filename)
+}
+
+// Read a CSV track from r, filename names it in errors and sets the metadata
+// defaults
+func Read(r io.Reader, filename string, closed bool) (*Track, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := readMeta(bytes.NewReader(data), filename)
+	if err != nil {
+		return nil, err
+	}
+	points, err := readPoints(bytes.NewReader(data), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Track{Meta: meta, Points: points, Closed: closed, File: filename}, nil
+}
+
+// Set a metadata field from its text, other keys are ignored
+func (m *Meta) set(key, value string) error {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "id":
+		m.ID = value
+	case "name":
+		m.Name = value
+	case "country":
+		m.Country = value
+	case "start":
+		start, err := strconv.Atoi(value)
+		if err != nil || start < 0 {
+			return fmt.Errorf("invalid start %q", value)
+		}
+		m.Start = start
+	case "crossover":
+		crossover, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid crossover %q", value)
+		}
+		m.Crossover = crossover
+	case "spacing":
+		spacing, err := strconv.ParseFloat(value, 32)
+		if err != nil || spacing < 0 {
+			return fmt.Errorf("invalid spacing %q", value)
+		}
+		m.Spacing = float32(spacing)
+	case "smoothing":
+		passes, err := strconv.Atoi(value)
+		if err != nil || passes < 0 {
+			return fmt.Errorf("invalid smoothing %q", value)
+		}
+		m.Smoothing = passes
+	}
+	return nil
+}
+
+// Metadata from the comments above the first row
+func readMeta(r io.Reader, filename string) (Meta, error) {
+	meta := defaultMeta(filename)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if !strings.HasPrefix(text, "#") {
+			break
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "#"), ":")
+		if !ok {
+			continue
+		}
+		if err := meta.set(key, value); err != nil {
+			return meta, fmt.Errorf("%s:%d: %v", filename, line, err)
+		}
+	}
+	return meta, scanner.Err()
+}
+
+// Centerline points, every bad row is an error naming its line
+func readPoints(r io.Reader, filename string) ([]Point, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1 // elevation and banking are optional
+	reader.TrimLeadingSpace = true
+
+	var columns map[string]int
+	var points []Point
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		// The first row names the columns unless it starts with a number
+		if columns == nil {
+			if _, err := strconv.ParseFloat(record[0], 32); err != nil {
+				columns, err = headerColumns(record)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+				}
+				continue
+			}
+			columns = make(map[string]int)
+			for i, name := range defaultColumns {
+				columns[name] = i
+			}
+		}
+
+		values := make(map[string]float32)
+		for name, i := range columns {
+			if i >= len(record) {
+				if slices.Contains(requiredColumns, name) {
+					return nil, fmt.Errorf("%s:%d: expected at least %d fields, got %d",
+						filename, line, len(requiredColumns), len(record))
+				}
+				continue
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[i]), 32)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, fmt.Errorf("%s:%d: invalid %s %q", filename, line, name, record[i])
+			}
+			values[name] = float32(value)
+		}
+
+		points = append(points, Point{
+			X:          values["x"],
+			Y:          values["y"],
+			Z:          values["z"],
+			WidthLeft:  values["wide_left"],
+			WidthRight: values["wide_right"],
+			Banking:    values["banking"],
+			Line:       line,
+		})
+	}
+
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%s: no points", filename)
+	}
+	return points, nil
+}
+
+// Column of each known name in a header row
+func headerColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		column, ok := columnNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			continue
+		}
+		if _, ok := columns[column]; ok {
+			return nil, fmt.Errorf("column %s given twice", column)
+		}
+		columns[column] = i
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+	return columns, nil
+}
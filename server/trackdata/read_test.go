@@ -0,0 +1,64 @@
+package trackdata
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Rows of a circular track of n points, 10 m wide, with elevation and banking
+func circleRows(n int, radius float64) []string {
+	rows := make([]string, n)
+	for i := range rows {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		rows[i] = fmt.Sprintf("%.3f,%.3f,5,5,0,0", radius*math.Cos(angle), radius*math.Sin(angle))
+	}
+	return rows
+}
+
+// A track file with a metadata line and a header, so point i is on line i+3
+func trackFile(rows []string) string {
+	return "# name: Test\nx,y,wide_right,wide_left,z,banking\n" + strings.Join(rows, "\n") + "\n"
+}
+
+func TestRead(t *testing.T) {
+	track, err := Read(strings.NewReader(trackFile(circleRows(36, 100))), "test.csv", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track.Meta.Name != "Test" || track.Meta.ID != "test" {
+		t.Errorf("metadata %+v", track.Meta)
+	}
+	if len(track.Points) != 36 {
+		t.Fatalf("%d points, want 36", len(track.Points))
+	}
+	if p := track.Points[9]; p.Line != 12 || p.WidthLeft != 5 || p.Y != 100 {
+		t.Errorf("point 9 %+v, want line 12, 5 m left and y 100", p)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"bad metadata", "# spacing: -1\n0,0,5,5\n", "test.csv:1: invalid spacing"},
+		{"missing column", "x,y,wide_left\n0,0,5\n", "test.csv:1: missing column wide_right"},
+		{"column twice", "x,x_m,y,wide_left,wide_right\n", "test.csv:1: column x given twice"},
+		{"bad number", "0,0,5,5\n10,ten,5,5\n", `test.csv:2: invalid y "ten"`},
+		{"not finite", "0,0,5,5\n10,0,NaN,5\n", "test.csv:2: invalid wide_right"},
+		{"too few fields", "# name: Test\n\n0,0,5,5\n10,0,5\n", "test.csv:4: expected at least 4 fields, got 3"},
+		{"no points", "# name: Test\nx,y,wide_right,wide_left\n", "test.csv: no points"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(test.file), "test.csv", true)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("error %v, want %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package trackdata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const curveSteps = 16 // straight pieces per curve or arc of an SVG path
+
+// Numbers each SVG path command takes
+var pathArgs = map[byte]int{'m': 2, 'l': 2, 'h': 1, 'v': 1, 'c': 6, 's': 4, 'q': 4, 't': 2, 'a': 7, 'z': 0}
+
+// Commands and numbers of SVG path data
+var pathTokens = regexp.MustCompile(`[MmLlHhVvCcSsQqTtAaZz]|[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?`)
+
+// Read a track from an SVG drawing: the first subpath of the path with id
+// "centerline", or of the first path if none has it. The path's data-width
+// attribute sets the width in metres, data-scale the metres per SVG unit, and
+// any other data- attributes the metadata. SVG's y axis points down, so it is
+// turned up the other way to keep the drawing the right way round.
+// Transforms are not applied.
+func ReadSVG(r io.Reader, filename string, closed bool) (*Track, error) {
+	var path map[string]string
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "path" {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range element.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		if path == nil || attrs["id"] == "centerline" {
+			path = attrs
+		}
+		if attrs["id"] == "centerline" {
+			break
+		}
+	}
+	if path == nil {
+		return nil, fmt.Errorf("%s: no path", filename)
+	}
+
+	scale, width := float32(1), importWidth
+	meta := defaultMeta(filename)
+	for key, value := range path {
+		name, ok := strings.CutPrefix(key, "data-")
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "scale", "width":
+			v, err := strconv.ParseFloat(value, 32)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("%s: invalid %s %q", filename, key, value)
+			}
+			if name == "scale" {
+				scale = float32(v)
+			} else {
+				width = float32(v)
+			}
+		default:
+			if err := meta.set(name, value); err != nil {
+				return nil, fmt.Errorf("%s: %v", filename, err)
+			}
+		}
+	}
+
+	xs, ys, err := flattenPath(path["d"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: path: %v", filename, err)
+	}
+
+	line := importedLine{defaultLeft: width / 2, defaultRight: width / 2}
+	for i := range xs {
+		line.xs = append(line.xs, float32(xs[i])*scale)
+		line.ys = append(line.ys, -float32(ys[i])*scale)
+	}
+	return line.track(meta, filename, closed)
+}
+
+// Points along the first subpath of SVG path data, curves and arcs cut into
+// straight pieces
+func flattenPath(data string) ([]float64, []float64, error) {
+	tokens := pathTokens.FindAllString(data, -1)
+
+	var xs, ys []float64
+	var x, y, startX, startY float64
+	var ctrlX, ctrlY float64 // last control point, for smooth curves
+	var command, previous byte
+	add := func(px, py float64) {
+		x, y = px, py
+		xs, ys = append(xs, px), append(ys, py)
+	}
+
+	for i := 0; i < len(tokens); {
+		if c := tokens[i][0]; strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0 {
+			command = c
+			i++
+		} else if command == 0 {
+			return nil, nil, fmt.Errorf("number %q without a command", tokens[i])
+		}
+
+		// Arguments for one use of the command, which may repeat
+		lower := command | 0x20
+		n := pathArgs[lower]
+		if i+n > len(tokens) {
+			return nil, nil, fmt.Errorf("%c needs %d numbers", command, n)
+		}
+		args := make([]float64, n)
+		for j := range args {
+			v, err := strconv.ParseFloat(tokens[i+j], 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid number %q", tokens[i+j])
+			}
+			args[j] = v
+		}
+		i += n
+
+		// Relative commands count from the current point
+		relative := command == lower
+		dx, dy := 0.0, 0.0
+		if relative {
+			dx, dy = x, y
+		}
+
+		switch lower {
+		case 'm':
+			if len(xs) > 0 {
+				// Only the first subpath is the centerline
+				return xs, ys, nil
+			}
+			add(args[0]+dx, args[1]+dy)
+			startX, startY = x, y
+			// Further pairs after a move are lines
+			if relative {
+				command = 'l'
+			} else {
+				command = 'L'
+			}
+		case 'l':
+			add(args[0]+dx, args[1]+dy)
+		case 'h':
+			add(args[0]+dx, y)
+		case 'v':
+			add(x, args[0]+dy)
+		case 'c', 's', 'q', 't':
+			x0, y0 := x, y
+			// Smooth curves reflect the last control point, or start from
+			// the current point after any other command
+			reflectX, reflectY := x0, y0
+			if (lower == 's' && strings.IndexByte("cs", previous) >= 0) ||
+				(lower == 't' && strings.IndexByte("qt", previous) >= 0) {
+				reflectX, reflectY = 2*x0-ctrlX, 2*y0-ctrlY
+			}
+
+			var x1, y1, x2, y2, ex, ey float64
+			switch lower {
+			case 'c':
+				x1, y1, x2, y2, ex, ey = args[0]+dx, args[1]+dy, args[2]+dx, args[3]+dy, args[4]+dx, args[5]+dy
+			case 's':
+				x1, y1, x2, y2, ex, ey = reflectX, reflectY, args[0]+dx, args[1]+dy, args[2]+dx, args[3]+dy
+			case 'q':
+				x1, y1, ex, ey = args[0]+dx, args[1]+dy, args[2]+dx, args[3]+dy
+			case 't':
+				x1, y1, ex, ey = reflectX, reflectY, args[0]+dx, args[1]+dy
+			}
+
+			for step := 1; step <= curveSteps; step++ {
+				t := float64(step) / curveSteps
+				u := 1 - t
+				if lower == 'c' || lower == 's' {
+					add(u*u*u*x0+3*u*u*t*x1+3*u*t*t*x2+t*t*t*ex, u*u*u*y0+3*u*u*t*y1+3*u*t*t*y2+t*t*t*ey)
+				} else {
+					add(u*u*x0+2*u*t*x1+t*t*ex, u*u*y0+2*u*t*y1+t*t*ey)
+				}
+			}
+			ctrlX, ctrlY = x1, y1
+			if lower == 'c' || lower == 's' {
+				ctrlX, ctrlY = x2, y2
+			}
+		case 'a':
+			for _, point := range arcPoints(x, y, args[0], args[1], args[2], args[3] != 0, args[4] != 0, args[5]+dx, args[6]+dy) {
+				add(point[0], point[1])
+			}
+		case 'z':
+			add(startX, startY)
+			command = 0
+		}
+		previous = lower
+	}
+	return xs, ys, nil
+}
+
+// Points along an SVG elliptical arc from (x0, y0) to (x, y), after the
+// endpoint to centre conversion in the SVG specification, appendix B.2.4
+func arcPoints(x0, y0, rx, ry, rotation float64, large, sweep bool, x, y float64) [][2]float64 {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || (x0 == x && y0 == y) {
+		return [][2]float64{{x, y}}
+	}
+
+	sinPhi, cosPhi := math.Sincos(rotation * math.Pi / 180)
+	mx, my := (x0-x)/2, (y0-y)/2
+	x1 := cosPhi*mx + sinPhi*my
+	y1 := -sinPhi*mx + cosPhi*my
+
+	// Radii too small to reach are scaled up until they just do
+	if scale := x1*x1/(rx*rx) + y1*y1/(ry*ry); scale > 1 {
+		rx, ry = rx*math.Sqrt(scale), ry*math.Sqrt(scale)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	factor := math.Sqrt(max(num/den, 0))
+	if large == sweep {
+		factor = -factor
+	}
+	cx1, cy1 := factor*rx*y1/ry, -factor*ry*x1/rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (x0+x)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (y0+y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	start := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	points := make([][2]float64, 0, curveSteps)
+	for step := 1; step <= curveSteps; step++ {
+		theta := start + delta*float64(step)/curveSteps
+		sin, cos := math.Sincos(theta)
+		points = append(points, [2]float64{
+			cosPhi*rx*cos - sinPhi*ry*sin + cx,
+			sinPhi*rx*cos + cosPhi*ry*sin + cy,
+		})
+	}
+	points[len(points)-1] = [2]float64{x, y}
+	return points
+}
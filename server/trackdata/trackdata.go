@@ -0,0 +1,80 @@
+// Package trackdata reads track and pit lane files, checks them and prepares
+// them for the simulation.
+//
+// A track file is a CSV of centerline points with the track width either
+// side, and optionally elevation and banking. Metadata sits in "# key: value"
+// comments above the points.
+package trackdata
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// A centerline point
+type Point struct {
+	X, Y       float32
+	Z          float32 // elevation, metres
+	WidthLeft  float32
+	WidthRight float32
+	Banking    float32 // degrees, positive raises the left edge
+	Line       int     // line in the file the point comes from, 0 if made up
+}
+
+// What a track file says about itself. Spacing and Smoothing ask Prepare to
+// resample and smooth the centerline.
+type Meta struct {
+	ID        string
+	Name      string
+	Country   string
+	Start     int     // point on the start/finish line
+	Crossover bool    // the track passes over itself, as at Suzuka
+	Spacing   float32 // metres between points after resampling, 0 keeps the points
+	Smoothing int     // smoothing passes over the centerline
+}
+
+// A track, a closed loop, or a pit lane, open from entry to exit
+type Track struct {
+	Meta   Meta
+	Points []Point
+	Closed bool
+	File   string // where the track was read from, for errors
+}
+
+// Metadata defaults for a file: the id is the file name in lower case, the
+// name the file name
+func defaultMeta(filename string) Meta {
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return Meta{
+		ID:   strings.ToLower(strings.ReplaceAll(base, ".", "-")),
+		Name: base,
+	}
+}
+
+// Number of segments along the centerline, counting the one back to the start
+// on a closed track
+func (t *Track) segments() int {
+	if t.Closed {
+		return len(t.Points)
+	}
+	return len(t.Points) - 1
+}
+
+// End points of a segment
+func (t *Track) segment(i int) (Point, Point) {
+	return t.Points[i], t.Points[(i+1)%len(t.Points)]
+}
+
+func distance(a, b Point) float32 {
+	return float32(math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y)))
+}
+
+// Length along the centerline
+func (t *Track) Length() float32 {
+	length := float32(0)
+	for i := 0; i < t.segments(); i++ {
+		length += distance(t.segment(i))
+	}
+	return length
+}
@@ -0,0 +1,77 @@
+package trackdata
+
+import (
+	"math"
+	"slices"
+)
+
+// Reflect the track across the y axis. Left and right swap over, so a
+// clockwise track becomes an anticlockwise one.
+func (t *Track) Mirror() {
+	for i := range t.Points {
+		point := &t.Points[i]
+		point.X = -point.X
+		point.WidthLeft, point.WidthRight = point.WidthRight, point.WidthLeft
+		point.Banking = -point.Banking
+	}
+}
+
+// Drive the track the other way round from the same start/finish line
+func (t *Track) Reverse() {
+	slices.Reverse(t.Points)
+	if t.Closed && len(t.Points) > 0 {
+		// The old first point ends up last, bring it back to the front
+		last := t.Points[len(t.Points)-1]
+		copy(t.Points[1:], t.Points[:len(t.Points)-1])
+		t.Points[0] = last
+	}
+
+	for i := range t.Points {
+		point := &t.Points[i]
+		point.WidthLeft, point.WidthRight = point.WidthRight, point.WidthLeft
+		point.Banking = -point.Banking
+	}
+}
+
+// Turn the track anticlockwise about the origin
+func (t *Track) Rotate(degrees float32) {
+	sin, cos := math.Sincos(float64(degrees) * math.Pi / 180)
+	for i := range t.Points {
+		point := &t.Points[i]
+		x, y := float64(point.X), float64(point.Y)
+		point.X = float32(x*cos - y*sin)
+		point.Y = float32(x*sin + y*cos)
+	}
+}
+
+// Scale the track about the origin, widths and elevation included
+func (t *Track) Scale(factor float32) {
+	for i := range t.Points {
+		point := &t.Points[i]
+		point.X *= factor
+		point.Y *= factor
+		point.Z *= factor
+		point.WidthLeft *= factor
+		point.WidthRight *= factor
+	}
+}
+
+// Move the track
+func (t *Track) Translate(dx, dy, dz float32) {
+	for i := range t.Points {
+		t.Points[i].X += dx
+		t.Points[i].Y += dy
+		t.Points[i].Z += dz
+	}
+}
+
+// Point closest to a position, for picking a start/finish line
+func (t *Track) Nearest(x, y float32) int {
+	nearest, best := 0, float32(math.Inf(1))
+	for i, point := range t.Points {
+		if d := distance(point, Point{X: x, Y: y}); d < best {
+			nearest, best = i, d
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,185 @@
+package trackdata
+
+import (
+	"fmt"
+	"math"
+
+	pb "server/proto"
+)
+
+const (
+	minSegment   = float32(0.01) // metres, shorter segments are degenerate
+	maxBanking   = float32(60)   // degrees either way
+	pinchWindow  = 8             // segments apart an edge may cross itself, where a hairpin pinches its inside
+	pinchLength  = float32(1.0)  // metres an edge may run backwards in such a pinch
+	crossingCell = float32(20)   // metres, grid cell size when looking for crossings
+)
+
+// Check that a track can be raced on: enough points, sensible widths and
+// banking, no zero-length segments, and a centerline and edges that neither
+// cross themselves nor each other nor fold back in corners tighter than the
+// track is wide. The inside edge of a hairpin may pinch into a small loop,
+// and a track marked as a crossover may run over itself on a bridge.
+func (t *Track) Validate() error {
+	minPoints := 2
+	if t.Closed {
+		minPoints = 3
+	}
+	if len(t.Points) < minPoints {
+		return fmt.Errorf("%s: needs at least %d points, has %d", t.File, minPoints, len(t.Points))
+	}
+
+	for i, point := range t.Points {
+		switch {
+		case point.WidthLeft <= 0 || point.WidthRight <= 0:
+			return t.pointError(i, "width must be positive, got %g left and %g right", point.WidthLeft, point.WidthRight)
+		case point.Banking <= -maxBanking || point.Banking >= maxBanking:
+			return t.pointError(i, "banking %g outside ±%g degrees", point.Banking, maxBanking)
+		}
+	}
+	for i := 0; i < t.segments(); i++ {
+		if distance(t.segment(i)) < minSegment {
+			return t.pointError(i, "repeats the next point")
+		}
+	}
+
+	centerline := make([]*pb.Point3D, len(t.Points))
+	for i, point := range t.Points {
+		centerline[i] = &pb.Point3D{X: point.X, Y: point.Y}
+	}
+	left, right := t.Boundaries()
+
+	for _, edge := range []struct {
+		name   string
+		points []*pb.Point3D
+		window int
+		folds  bool // can fold back; the centerline is what folds are measured against
+	}{{"centerline", centerline, 2, false}, {"left edge", left, pinchWindow, true}, {"right edge", right, pinchWindow, true}} {
+		if edge.folds {
+			if i, ok := t.foldBack(edge.points); ok {
+				return t.pointError(i, "%s folds back, the corner is tighter than the track is wide", edge.name)
+			}
+		}
+		if t.Meta.Crossover {
+			continue
+		}
+		if i, j, ok := t.selfCrossing(edge.points, edge.window); ok {
+			return t.pointError(i, "%s crosses itself near point %d", edge.name, j)
+		}
+	}
+	if i, ok := t.edgesCross(left, right); ok && !t.Meta.Crossover {
+		return t.pointError(i, "left and right edges cross")
+	}
+	return nil
+}
+
+// Error about a point, naming the line it came from when there is one
+func (t *Track) pointError(i int, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	if line := t.Points[i].Line; line > 0 {
+		return fmt.Errorf("%s:%d: %s", t.File, line, message)
+	}
+	return fmt.Errorf("%s: point %d: %s", t.File, i, message)
+}
+
+// First pair of segments of a polyline that cross, leaving out pairs less
+// than window segments apart
+func (t *Track) selfCrossing(points []*pb.Point3D, window int) (int, int, bool) {
+	segments := t.segments()
+	return t.crossing(points, points, func(i, j int) bool {
+		apart := max(i-j, j-i)
+		if t.Closed {
+			apart = min(apart, segments-apart)
+		}
+		return apart < window
+	})
+}
+
+// First segment of a stretch of edge that runs backwards against the
+// centerline further than a pinch
+func (t *Track) foldBack(edge []*pb.Point3D) (int, bool) {
+	start, backwards := 0, float32(0)
+	for i := 0; i < t.segments(); i++ {
+		a, b := t.segment(i)
+		next := (i + 1) % len(edge)
+		along := ((edge[next].X-edge[i].X)*(b.X-a.X) + (edge[next].Y-edge[i].Y)*(b.Y-a.Y)) / distance(a, b)
+		if along >= 0 {
+			backwards = 0
+			continue
+		}
+
+		if backwards == 0 {
+			start = i
+		}
+		backwards -= along
+		if backwards > pinchLength {
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+// First segment of the left edge that crosses the right edge
+func (t *Track) edgesCross(left, right []*pb.Point3D) (int, bool) {
+	i, _, ok := t.crossing(left, right, func(i, j int) bool { return false })
+	return i, ok
+}
+
+// First pair of segments i of p and j of q that cross, unless skip(i, j).
+// Segments of q are binned into a grid so only neighbours are compared.
+func (t *Track) crossing(p, q []*pb.Point3D, skip func(i, j int) bool) (int, int, bool) {
+	cells := func(a, b *pb.Point3D, visit func(cell [2]int) bool) bool {
+		col0, row0 := int(math.Floor(float64(min(a.X, b.X)/crossingCell))), int(math.Floor(float64(min(a.Y, b.Y)/crossingCell)))
+		col1, row1 := int(math.Floor(float64(max(a.X, b.X)/crossingCell))), int(math.Floor(float64(max(a.Y, b.Y)/crossingCell)))
+		for col := col0; col <= col1; col++ {
+			for row := row0; row <= row1; row++ {
+				if visit([2]int{col, row}) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	segments := t.segments()
+	grid := make(map[[2]int][]int)
+	for j := 0; j < segments; j++ {
+		cells(q[j], q[(j+1)%len(q)], func(cell [2]int) bool {
+			grid[cell] = append(grid[cell], j)
+			return false
+		})
+	}
+
+	for i := 0; i < segments; i++ {
+		a, b := p[i], p[(i+1)%len(p)]
+		found := -1
+		cells(a, b, func(cell [2]int) bool {
+			for _, j := range grid[cell] {
+				if !skip(i, j) && segmentsCross(a, b, q[j], q[(j+1)%len(q)]) {
+					found = j
+					return true
+				}
+			}
+			return false
+		})
+		if found >= 0 {
+			return i, found, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Whether segments ab and cd cross, touching ends do not count
+func segmentsCross(a, b, c, d *pb.Point3D) bool {
+	if max(a.X, b.X) < min(c.X, d.X) || max(c.X, d.X) < min(a.X, b.X) ||
+		max(a.Y, b.Y) < min(c.Y, d.Y) || max(c.Y, d.Y) < min(a.Y, b.Y) {
+		return false
+	}
+
+	side := func(p, q, r *pb.Point3D) float64 {
+		return float64(q.X-p.X)*float64(r.Y-p.Y) - float64(q.Y-p.Y)*float64(r.X-p.X)
+	}
+	d1, d2 := side(a, b, c), side(a, b, d)
+	d3, d4 := side(c, d, a), side(c, d, b)
+	return d1*d2 < 0 && d3*d4 < 0 && !math.IsNaN(d1*d2+d3*d4)
+}
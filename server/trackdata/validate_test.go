@@ -0,0 +1,69 @@
+package trackdata
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(rows []string) []string
+		want string // in the error, empty for a valid track
+	}{
+		{"valid", func(rows []string) []string { return rows }, ""},
+		{"too few points", func(rows []string) []string { return rows[:2] }, "test.csv: needs at least 3 points, has 2"},
+		{"no width", func(rows []string) []string {
+			rows[5] = strings.Replace(rows[5], ",5,5,", ",0,5,", 1)
+			return rows
+		}, "test.csv:8: width must be positive"},
+		{"banking", func(rows []string) []string {
+			rows[10] = strings.TrimSuffix(rows[10], ",0") + ",75"
+			return rows
+		}, "test.csv:13: banking 75 outside"},
+		{"repeated point", func(rows []string) []string {
+			rows[7] = rows[6]
+			return rows
+		}, "test.csv:9: repeats the next point"},
+		{"too wide for the corner", func(rows []string) []string {
+			for i := range rows {
+				rows[i] = strings.Replace(rows[i], ",5,5,", ",5,150,", 1)
+			}
+			return rows
+		}, "test.csv:3: left edge folds back"},
+		{"too wide for the corner, clockwise", func(rows []string) []string {
+			slices.Reverse(rows)
+			for i := range rows {
+				rows[i] = strings.Replace(rows[i], ",5,5,", ",150,5,", 1)
+			}
+			return rows
+		}, "test.csv:3: right edge folds back"},
+		{"wide outside", func(rows []string) []string {
+			for i := range rows {
+				rows[i] = strings.Replace(rows[i], ",5,5,", ",150,5,", 1)
+			}
+			return rows
+		}, ""},
+		{"figure of eight", func(rows []string) []string {
+			rows[9], rows[27] = rows[27], rows[9]
+			return rows
+		}, "crosses itself"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			track, err := Read(strings.NewReader(trackFile(test.edit(circleRows(36, 100)))), "test.csv", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = track.Validate()
+			switch {
+			case test.want == "" && err != nil:
+				t.Errorf("valid track: %v", err)
+			case test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)):
+				t.Errorf("error %v, want %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package trackdata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Layout of a written track file
+type Format int
+
+const (
+	FormatCSV Format = iota // ours, with a header row and metadata comments
+	FormatTUM               // the TUM race track database's, header commented out
+)
+
+// Format by name, as given on the command line
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "csv", "":
+		return FormatCSV, nil
+	case "tum":
+		return FormatTUM, nil
+	}
+	return 0, fmt.Errorf("unknown track format %q", name)
+}
+
+// Write the track to a file
+func (t *Track) Save(filename string, format Format) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(file, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Write the track with its metadata. The points go out as they are, so the
+// start, spacing and smoothing are not written again. Elevation and banking
+// are left out when the track is flat.
+func (t *Track) Write(w io.Writer, format Format) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# id: %s\n", t.Meta.ID)
+	fmt.Fprintf(out, "# name: %s\n", t.Meta.Name)
+	if t.Meta.Country != "" {
+		fmt.Fprintf(out, "# country: %s\n", t.Meta.Country)
+	}
+	if t.Meta.Crossover {
+		fmt.Fprintf(out, "# crossover: true\n")
+	}
+
+	flat := true
+	for _, point := range t.Points {
+		if point.Z != 0 || point.Banking != 0 {
+			flat = false
+			break
+		}
+	}
+
+	header := "x,y,wide_right,wide_left"
+	if format == FormatTUM {
+		header = "# x_m,y_m,w_tr_right_m,w_tr_left_m"
+	}
+	if !flat {
+		header += ",z,banking"
+		if format == FormatTUM {
+			header = strings.Replace(header, ",z,", ",z_m,", 1)
+		}
+	}
+	fmt.Fprintln(out, header)
+
+	for _, point := range t.Points {
+		fmt.Fprintf(out, "%.6f,%.6f,%.3f,%.3f", point.X, point.Y, point.WidthRight, point.WidthLeft)
+		if !flat {
+			fmt.Fprintf(out, ",%.3f,%.2f", point.Z, point.Banking)
+		}
+		fmt.Fprintln(out)
+	}
+	return out.Flush()
+}